@@ -0,0 +1,95 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var (
+	aliasMutex   sync.RWMutex
+	levelAliases = map[string]Level{}
+	customLevels []Level
+)
+
+// builtinLevels are the levels GetLogLevel knows without registering them
+var builtinLevels = []Level{TRACE, DEBUG, INFO, WARNING, ERROR, FATAL}
+
+// RegisterLevel adds a level GetLogLevel resolves by its text. A level can not reuse the text of another level
+// or an alias, registering the same level again is allowed.
+func RegisterLevel(level Level) error {
+	if strings.TrimSpace(level.Text) == "" {
+		return fmt.Errorf("level text can not be empty")
+	}
+
+	aliasMutex.Lock()
+	defer aliasMutex.Unlock()
+
+	if _, ok := levelAliases[strings.ToLower(level.Text)]; ok {
+		return fmt.Errorf("level %s conflicts with a level alias", level.Text)
+	}
+
+	for _, existing := range allLevels() {
+		if existing.Text == level.Text {
+			if existing == level {
+				return nil
+			}
+			return fmt.Errorf("level %s is already registered with severity %d", level.Text, existing.Severity)
+		}
+	}
+
+	customLevels = append(customLevels, level)
+	return nil
+}
+
+// allLevels gets the built in and registered levels, aliasMutex must be held
+func allLevels() []Level {
+	return append(append(make([]Level, 0, len(builtinLevels)+len(customLevels)), builtinLevels...), customLevels...)
+}
+
+func lookupLevel(text string) (Level, bool) {
+	aliasMutex.RLock()
+	defer aliasMutex.RUnlock()
+
+	for _, level := range allLevels() {
+		if level.Text == text {
+			return level, true
+		}
+	}
+
+	return Level{}, false
+}
+
+// RegisterLevelAlias registers another name that GetLogLevel resolves to the level e.g. "CRIT" for FATAL.
+// Aliases are case-insensitive. An alias can not replace one of the level names or an alias already registered
+// for a different level, registering the same alias for the same level again is allowed.
+func RegisterLevelAlias(alias string, level Level) error {
+	key := strings.ToLower(strings.TrimSpace(alias))
+	if key == "" {
+		return fmt.Errorf("level alias can not be empty")
+	}
+
+	aliasMutex.Lock()
+	defer aliasMutex.Unlock()
+
+	for _, existing := range allLevels() {
+		if strings.ToLower(existing.Text) == key {
+			return fmt.Errorf("level alias %s conflicts with the %s level", alias, existing.Text)
+		}
+	}
+
+	if existing, ok := levelAliases[key]; ok && existing != level {
+		return fmt.Errorf("level alias %s is already registered for the %s level", alias, existing.Text)
+	}
+
+	levelAliases[key] = level
+	return nil
+}
+
+func lookupLevelAlias(alias string) (Level, bool) {
+	aliasMutex.RLock()
+	defer aliasMutex.RUnlock()
+
+	level, ok := levelAliases[strings.ToLower(strings.TrimSpace(alias))]
+	return level, ok
+}
@@ -0,0 +1,51 @@
+package log
+
+import "testing"
+
+func TestRegisterLevelAliasConflictsWithLevelName(t *testing.T) {
+	for _, alias := range []string{"Error", "error", " WARNING "} {
+		if err := RegisterLevelAlias(alias, INFO); err == nil {
+			t.Errorf("alias %q should conflict with a level name", alias)
+		}
+	}
+}
+
+func TestRegisterLevelAliasForTwoLevels(t *testing.T) {
+	if err := RegisterLevelAlias("test-crit", FATAL); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := RegisterLevelAlias("test-crit", ERROR); err == nil {
+		t.Error("alias registered for a second level")
+	}
+
+	if level := GetLogLevel("test-crit"); level != FATAL {
+		t.Errorf("alias resolved to %s, expected %s", level.Text, FATAL.Text)
+	}
+}
+
+func TestRegisterLevelAliasAgain(t *testing.T) {
+	if err := RegisterLevelAlias("test-warn", WARNING); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := RegisterLevelAlias("test-warn", WARNING); err != nil {
+		t.Errorf("registering the same alias again should be allowed: %s", err)
+	}
+}
+
+func TestLevelAliasIsCaseInsensitive(t *testing.T) {
+	if err := RegisterLevelAlias("Test-Verbose", DEBUG); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := RegisterLevelAlias("TEST-VERBOSE", INFO); err == nil {
+		t.Error("alias differing only in case registered for a second level")
+	}
+
+	for _, text := range []string{"test-verbose", "TEST-VERBOSE", "Test-Verbose"} {
+		if level := GetLogLevel(text); level != DEBUG {
+			t.Errorf("%q resolved to %s, expected %s", text, level.Text, DEBUG.Text)
+		}
+	}
+}
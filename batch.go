@@ -0,0 +1,87 @@
+package log
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultBatchInterval is how often batched messages are published when Settings.BatchInterval is not set
+const DefaultBatchInterval = time.Second
+
+// batcher collects entries so they are published together
+type batcher struct {
+	mutex   sync.Mutex
+	size    int
+	entries []entry
+	closed  bool
+}
+
+func newBatcher(size int) *batcher {
+	if size <= 1 {
+		return nil
+	}
+
+	return &batcher{size: size}
+}
+
+// add the entries to the batch, the batch is returned once it is full or right away if it has a fatal message.
+// After the batcher is closed the entries are returned as they are.
+func (b *batcher) add(job []entry) []entry {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return job
+	}
+
+	fatal := false
+	for _, e := range job {
+		fatal = fatal || e.message.Level.Severity >= FATAL.Severity
+	}
+
+	b.entries = append(b.entries, job...)
+	if len(b.entries) < b.size && !fatal {
+		return nil
+	}
+
+	full := b.entries
+	b.entries = nil
+	return full
+}
+
+// take the entries collected so far
+func (b *batcher) take(close bool) []entry {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	entries := b.entries
+	b.entries = nil
+	b.closed = b.closed || close
+	return entries
+}
+
+// flushBatch publishes the entries collected so far
+func (l logger) flushBatch(close bool) {
+	if l.batcher == nil {
+		return
+	}
+
+	if entries := l.batcher.take(close); len(entries) != 0 {
+		l.enqueue(entries)
+	}
+}
+
+// flushBatches publishes the collected entries on every interval so messages are not held for long
+func (l logger) flushBatches(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			l.flushBatch(false)
+		}
+	}
+}
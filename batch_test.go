@@ -0,0 +1,68 @@
+package log
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchRecordPublisher records the size of each batch it publishes
+type batchRecordPublisher struct {
+	recordPublisher
+	mutex   sync.Mutex
+	batches []int
+}
+
+func (p *batchRecordPublisher) PublishBatch(messages [][]byte) error {
+	p.mutex.Lock()
+	p.batches = append(p.batches, len(messages))
+	p.mutex.Unlock()
+
+	for _, message := range messages {
+		if err := p.Publish(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestBatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		count    int
+		expected []int
+	}{
+		{name: "full batches", size: 2, count: 4, expected: []int{2, 2}},
+		{name: "rest on flush", size: 3, count: 5, expected: []int{3, 2}},
+		{name: "one left is published on its own", size: 2, count: 5, expected: []int{2, 2}},
+		{name: "smaller than a batch", size: 10, count: 3, expected: []int{3}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			publisher := &batchRecordPublisher{}
+			l := Create(Settings{
+				MinLogLevel:      INFO,
+				BatchSize:        test.size,
+				BatchInterval:    time.Hour,
+				CustomPublishers: []CustomPublisher{{Name: "batch", Publisher: publisher}},
+			})
+
+			texts := make([]string, test.count)
+			for i := range texts {
+				texts[i] = string(rune('a' + i))
+				l.Print(texts[i])
+			}
+			l.Flush()
+
+			if !reflect.DeepEqual(publisher.batches, test.expected) {
+				t.Errorf("batches are %v, expected %v", publisher.batches, test.expected)
+			}
+			if published := publisher.published(); !reflect.DeepEqual(published, texts) {
+				t.Errorf("published %v, expected %v", published, texts)
+			}
+		})
+	}
+}
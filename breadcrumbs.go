@@ -0,0 +1,68 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+)
+
+// BreadcrumbsField is the field error level messages carry their breadcrumbs in
+const BreadcrumbsField = "breadcrumbs"
+
+// Breadcrumb is a recent message attached to an error
+type Breadcrumb struct {
+	Time  int64  `json:"time"`
+	Level string `json:"level"`
+	Text  string `json:"text"`
+}
+
+func (b Breadcrumb) String() string {
+	return fmt.Sprintf("[%s] %s", b.Level, b.Text)
+}
+
+// breadcrumbs is a fixed size ring buffer of the most recent messages
+type breadcrumbs struct {
+	mutex sync.Mutex
+	items []Breadcrumb
+	next  int
+	full  bool
+}
+
+func newBreadcrumbs(size int) *breadcrumbs {
+	if size <= 0 {
+		return nil
+	}
+
+	return &breadcrumbs{items: make([]Breadcrumb, size)}
+}
+
+// withOwnBreadcrumbs gives a derived logger its own breadcrumbs so its errors only carry the messages
+// of the same component or request
+func (l logger) withOwnBreadcrumbs() logger {
+	l.breadcrumbs = newBreadcrumbs(l.settings.Breadcrumbs)
+	return l
+}
+
+func (b *breadcrumbs) add(message Message) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.items[b.next] = Breadcrumb{Time: message.Time, Level: message.Level.Text, Text: message.Text}
+	b.next = (b.next + 1) % len(b.items)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// snapshot gets the stored messages oldest first
+func (b *breadcrumbs) snapshot() []Breadcrumb {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.full {
+		return append([]Breadcrumb(nil), b.items[:b.next]...)
+	}
+
+	result := make([]Breadcrumb, 0, len(b.items))
+	result = append(result, b.items[b.next:]...)
+	return append(result, b.items[:b.next]...)
+}
@@ -0,0 +1,134 @@
+package log
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const (
+	// packagePath import path of this package, frames from it are skipped when looking for the caller
+	packagePath = "github.com/cjburchell/uatu-go"
+	// maxCallerDepth the maximum number of frames searched for the caller
+	maxCallerDepth = 32
+)
+
+type pcInfo struct {
+	pkg      string
+	internal bool
+	writer   bool
+	file     string
+	line     int
+	function string
+}
+
+// writerFunctions are the functions of this package that the standard library calls to write for the user's code
+var writerFunctions = map[string]bool{
+	packagePath + ".Writer.Write":        true,
+	packagePath + ".(*LineWriter).Write": true,
+	packagePath + ".slogHandler.Handle":  true,
+}
+
+// writerPackages are standard library packages that call the writerFunctions for the user's code,
+// their frames are skipped only when they called one of the writerFunctions
+var writerPackages = map[string]bool{"fmt": true, "io": true, "bufio": true, "log": true, "log/slog": true}
+
+// pcCache caches the pcInfo for each program counter
+var pcCache sync.Map
+
+func lookupPC(pc uintptr) pcInfo {
+	if cached, ok := pcCache.Load(pc); ok {
+		return cached.(pcInfo)
+	}
+
+	var info pcInfo
+	if function := runtime.FuncForPC(pc - 1); function != nil {
+		name := function.Name()
+		info.pkg = packageName(name)
+		info.file, info.line = function.FileLine(pc - 1)
+		// the package's own tests call the logger like any other code
+		info.internal = info.pkg == packagePath && !strings.HasSuffix(info.file, "_test.go")
+		info.writer = writerFunctions[name]
+		info.function = name
+	}
+
+	pcCache.Store(pc, info)
+	return info
+}
+
+// packageName gets the package path from a fully qualified function name, the runtime escapes the dots
+// in the last element of the path so the first dot after the last slash ends the path
+// e.g. github.com/cjburchell/uatu-go.(*logger).Warn -> github.com/cjburchell/uatu-go
+// and gopkg.in/yaml%2ev2.Marshal -> gopkg.in/yaml.v2
+func packageName(function string) string {
+	lastSlash := strings.LastIndex(function, "/")
+	if lastSlash < 0 {
+		lastSlash = 0
+	}
+
+	if dot := strings.Index(function[lastSlash:], "."); dot >= 0 {
+		function = function[:lastSlash+dot]
+	}
+
+	return strings.Replace(function, "%2e", ".", -1)
+}
+
+// callerIndex finds the index of the first frame outside of this package so the result is the same no matter
+// which logging method was used, frames of the writerPackages are skipped when they called this package's writers
+func callerIndex(pcs []uintptr) int {
+	writing := false
+	for i, pc := range pcs {
+		info := lookupPC(pc)
+		if info.internal {
+			writing = writing || info.writer
+			continue
+		}
+
+		if writing && writerPackages[info.pkg] {
+			continue
+		}
+
+		return i
+	}
+
+	return -1
+}
+
+// callerPC finds the program counter of the code that called the logger
+func callerPC() (uintptr, bool) {
+	var pcs [maxCallerDepth]uintptr
+	count := runtime.Callers(2, pcs[:])
+	if i := callerIndex(pcs[:count]); i >= 0 {
+		return pcs[i], true
+	}
+
+	return 0, false
+}
+
+// callerPackage gets the package of the code that called the logger
+func callerPackage() string {
+	pc, ok := callerPC()
+	if !ok {
+		return ""
+	}
+
+	return lookupPC(pc).pkg
+}
+
+// callerLocation gets the file, line and function of the code that called the logger,
+// skip is the number of frames to skip after that for code that wraps the logger
+func callerLocation(skip int) (file string, line int, function string) {
+	var pcs [maxCallerDepth]uintptr
+	count := runtime.Callers(2, pcs[:])
+	i := callerIndex(pcs[:count])
+	if i < 0 {
+		return "", 0, ""
+	}
+
+	if i+skip < count {
+		i += skip
+	}
+
+	info := lookupPC(pcs[i])
+	return info.file, info.line, info.function
+}
@@ -0,0 +1,72 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPackageName(t *testing.T) {
+	tests := []struct {
+		name     string
+		function string
+		expected string
+	}{
+		{name: "method", function: "github.com/cjburchell/uatu-go.(*logger).Warn", expected: "github.com/cjburchell/uatu-go"},
+		{name: "closure", function: "github.com/cjburchell/uatu-go.Create.func1", expected: "github.com/cjburchell/uatu-go"},
+		{name: "dotted path", function: "gopkg.in/yaml%2ev2.Marshal", expected: "gopkg.in/yaml.v2"},
+		{name: "dotted path method", function: "gopkg.in/yaml%2ev2.(*encoder).marshal", expected: "gopkg.in/yaml.v2"},
+		{name: "dotted host", function: "example.com/app.main", expected: "example.com/app"},
+		{name: "standard library", function: "fmt.Fprintf", expected: "fmt"},
+		{name: "main", function: "main.main", expected: "main"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := packageName(test.function); result != test.expected {
+				t.Errorf("package is %s, expected %s", result, test.expected)
+			}
+		})
+	}
+}
+
+// callbackStringer logs when fmt calls it back
+type callbackStringer struct {
+	log ILog
+}
+
+func (s callbackStringer) String() string {
+	s.log.Print("from String")
+	return "stringer"
+}
+
+func TestCallerLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      func(l ILog)
+		function string
+	}{
+		{name: "method", log: func(l ILog) { l.Print("direct") }, function: "TestCallerLocation.func1"},
+		{name: "writer", log: func(l ILog) { fmt.Fprintf(l.GetWriter(INFO), "writer") }, function: "TestCallerLocation.func2"},
+		{name: "std logger", log: func(l ILog) { StdLogger(l, INFO).Print("std") }, function: "TestCallerLocation.func3"},
+		{name: "called back from fmt", log: func(l ILog) { _ = fmt.Sprint(callbackStringer{l}) }, function: "callbackStringer.String"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewMemoryLogger(Settings{MinLogLevel: INFO, IncludeCaller: true})
+			test.log(m)
+
+			messages := m.Messages()
+			if len(messages) != 1 {
+				t.Fatalf("logged %d messages, expected 1", len(messages))
+			}
+			if !strings.HasSuffix(messages[0].Func, "."+test.function) {
+				t.Errorf("caller is %s, expected %s", messages[0].Func, test.function)
+			}
+			if !strings.HasSuffix(messages[0].File, "caller_test.go") {
+				t.Errorf("caller file is %s, expected caller_test.go", messages[0].File)
+			}
+		})
+	}
+}
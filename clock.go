@@ -0,0 +1,58 @@
+package log
+
+import "time"
+
+// DefaultTimeFormat is the layout messages are printed with when Settings.TimeFormat is not set
+const DefaultTimeFormat = "2006-01-02 15:04:05 MST"
+
+// Clock provides the time messages are stamped with
+type Clock interface {
+	Now() time.Time
+}
+
+// TimerClock is a Clock that also runs the logger's timers, e.g. a fake clock in tests so the dedupe windows and
+// drop report intervals can be stepped through. The timers of a plain Clock run on the system time.
+type TimerClock interface {
+	Clock
+	// AfterFunc calls f in its own goroutine once d has passed, calling the returned function stops the timer
+	// and returns false if it already fired or was stopped
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// now gets the current time in milliseconds from Settings.Clock
+func (l logger) now() int64 {
+	return l.clock().Now().UnixNano() / int64(time.Millisecond)
+}
+
+func (l logger) clock() Clock {
+	return settingsClock(l.settings)
+}
+
+// settingsClock gets Settings.Clock or the system time if it is not set
+func settingsClock(settings Settings) Clock {
+	if settings.Clock == nil {
+		return realClock{}
+	}
+	return settings.Clock
+}
+
+// afterFunc calls f once d has passed on the clock
+func afterFunc(clock Clock, d time.Duration, f func()) (stop func() bool) {
+	if timers, ok := clock.(TimerClock); ok {
+		return timers.AfterFunc(d, f)
+	}
+	return time.AfterFunc(d, f).Stop
+}
+
+func (l logger) timeFormat() string {
+	if l.settings.TimeFormat != "" {
+		return l.settings.TimeFormat
+	}
+	return DefaultTimeFormat
+}
@@ -0,0 +1,130 @@
+package log
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errTest = errors.New("test error")
+
+// fakeClock is a TimerClock that only moves when it is advanced, due timers run in the advancing goroutine
+type fakeClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	at   time.Time
+	f    func()
+	done bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) func() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	timer := &fakeTimer{at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, timer)
+	return func() bool {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		stopped := !timer.done
+		timer.done = true
+		return stopped
+	}
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	c.now = c.now.Add(d)
+	var due []*fakeTimer
+	for _, timer := range c.timers {
+		if !timer.done && !timer.at.After(c.now) {
+			timer.done = true
+			due = append(due, timer)
+		}
+	}
+	c.mutex.Unlock()
+
+	for _, timer := range due {
+		timer.f()
+	}
+}
+
+func TestClockDrivesTimedBehaviour(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		log      func(l ILog, clock *fakeClock)
+		expected []string
+	}{
+		{
+			name:     "rate limit window",
+			settings: Settings{RateLimit: 1, SampleInterval: time.Second, DropReportThreshold: 100},
+			log: func(l ILog, clock *fakeClock) {
+				l.Print("first")
+				l.Print("limited")
+				clock.Advance(time.Second)
+				l.Print("next window")
+			},
+			expected: []string{"first", "next window"},
+		},
+		{
+			name:     "dedupe window",
+			settings: Settings{DedupeWindow: time.Minute},
+			log: func(l ILog, clock *fakeClock) {
+				for i := 0; i < 3; i++ {
+					l.Error(errTest, "failed")
+				}
+				clock.Advance(time.Minute)
+			},
+			expected: []string{"failed", "Error repeated 2 more times"},
+		},
+		{
+			name:     "drop report interval",
+			settings: Settings{RateLimit: 1, SampleInterval: time.Hour, DropReportInterval: time.Minute},
+			log: func(l ILog, clock *fakeClock) {
+				l.Print("first")
+				l.Print("limited")
+				clock.Advance(time.Minute)
+			},
+			expected: []string{"first", "Dropped 1 log messages (rateLimited: 1)"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clock := newFakeClock()
+			settings := test.settings
+			settings.MinLogLevel = INFO
+			settings.Clock = clock
+			m := NewMemoryLogger(settings)
+
+			test.log(m, clock)
+
+			messages := m.Messages()
+			if len(messages) != len(test.expected) {
+				t.Fatalf("logged %d messages, expected %d", len(messages), len(test.expected))
+			}
+			for i, message := range messages {
+				if !strings.HasPrefix(message.Text, test.expected[i]) {
+					t.Errorf("message %d is %q, expected it to start with %q", i, message.Text, test.expected[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package log
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// console writes each message to the writer as a single line so messages
+// logged from several goroutines never interleave
+type console struct {
+	mutex  sync.Mutex
+	writer io.Writer
+
+	// lines is set when the console is written by a background goroutine
+	lines   chan string
+	state   sync.RWMutex
+	closed  bool
+	stopped chan struct{}
+	onDrop  func()
+}
+
+// consoleWriter gets Settings.ConsoleWriter or stdout if it is not set
+func consoleWriter(settings Settings) io.Writer {
+	if settings.ConsoleWriter == nil {
+		return os.Stdout
+	}
+	return settings.ConsoleWriter
+}
+
+func newConsole(writer io.Writer) *console {
+	return &console{writer: writer}
+}
+
+// newAsyncConsole creates a console that is written by a background goroutine so logging does not wait on
+// a slow console, lines that do not fit in the buffer are dropped
+func newAsyncConsole(writer io.Writer, size int, onDrop func()) *console {
+	c := &console{
+		writer:  writer,
+		lines:   make(chan string, size),
+		stopped: make(chan struct{}),
+		onDrop:  onDrop,
+	}
+
+	go func() {
+		defer close(c.stopped)
+		for text := range c.lines {
+			c.writeLine(text)
+		}
+	}()
+
+	return c
+}
+
+func (c *console) write(text string) {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	if c.lines != nil {
+		c.state.RLock()
+		if !c.closed {
+			select {
+			case c.lines <- text:
+			default:
+				c.onDrop()
+			}
+			c.state.RUnlock()
+			return
+		}
+		c.state.RUnlock()
+	}
+
+	c.writeLine(text)
+}
+
+// writeNow writes the text right away, even on an async console
+func (c *console) writeNow(text string) {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	c.writeLine(text)
+}
+
+func (c *console) writeLine(text string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	_, _ = io.WriteString(c.writer, text)
+}
+
+// close writes the buffered lines and stops the background goroutine,
+// anything written afterwards is written directly
+func (c *console) close() {
+	if c.lines == nil {
+		return
+	}
+
+	c.state.Lock()
+	if c.closed {
+		c.state.Unlock()
+		return
+	}
+	c.closed = true
+	close(c.lines)
+	c.state.Unlock()
+
+	<-c.stopped
+}
@@ -0,0 +1,51 @@
+package log
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func BenchmarkConsoleWrite(b *testing.B) {
+	c := newConsole(ioutil.Discard)
+	text := "[Info] 2006-01-02 15:04:05 UTC service - a message logged from several goroutines"
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.write(text)
+		}
+	})
+}
+
+func TestConsoleWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+	}{
+		{name: "sync", settings: Settings{}},
+		{name: "async", settings: Settings{AsyncConsole: true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var output bytes.Buffer
+			settings := test.settings
+			settings.MinLogLevel = INFO
+			settings.LogToConsole = true
+			settings.ConsoleWriter = &output
+			settings.Clock = newFakeClock()
+			settings.ServiceName = "service"
+
+			l := Create(settings)
+			l.Print("hello")
+			if err := l.Close(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if expected := "[Info] 2020-01-01 00:00:00 UTC service - hello\n"; output.String() != expected {
+				t.Errorf("console output is %q, expected %q", output.String(), expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package log
+
+import (
+	"context"
+	"fmt"
+)
+
+// ContextReasonField is the field ContextError puts the reason the context ended in
+const ContextReasonField = "reason"
+
+// Reasons logged by ContextError
+const (
+	ContextCanceled         = "canceled"
+	ContextDeadlineExceeded = "deadline_exceeded"
+)
+
+// ContextError logs why the context ended, a canceled context is logged as a warning and
+// an exceeded deadline as an error. Nothing is logged if the context has not ended.
+func ContextError(l ILog, ctx context.Context, v ...interface{}) {
+	if ctx == nil || ctx.Err() == nil {
+		return
+	}
+
+	err := ctx.Err()
+	level, reason := ERROR, err.Error()
+	switch err {
+	case context.Canceled:
+		level, reason = WARNING, ContextCanceled
+	case context.DeadlineExceeded:
+		reason = ContextDeadlineExceeded
+	}
+
+	text := err.Error()
+	if msg := fmt.Sprint(v...); msg != "" {
+		text = fmt.Sprintf("%s: %s", msg, text)
+	}
+
+	l.WithField(ContextReasonField, reason).Log(level, text)
+}
+
+// Fields WithContext puts the ids from Settings.TraceExtractor in
+const (
+	TraceIDField = "trace_id"
+	SpanIDField  = "span_id"
+)
+
+// ContextKey is a context value that WithContext attaches to messages
+type ContextKey struct {
+	// Key the value is stored under in the context
+	Key interface{}
+	// Field the value is logged as
+	Field string
+}
+
+// WithContext creates a logger that adds the values of Settings.ContextKeys found in the context to every message
+// along with the trace and span ids from Settings.TraceExtractor. If the context is nil or has none of the values
+// the logger is returned unchanged.
+func (l logger) WithContext(ctx context.Context) ILog {
+	if ctx == nil {
+		return l
+	}
+
+	var fields map[string]interface{}
+	for _, key := range l.settings.ContextKeys {
+		value := ctx.Value(key.Key)
+		if value == nil {
+			continue
+		}
+
+		if fields == nil {
+			fields = make(map[string]interface{}, len(l.settings.ContextKeys))
+		}
+		fields[key.Field] = value
+	}
+
+	if l.settings.TraceExtractor != nil {
+		traceID, spanID := l.settings.TraceExtractor(ctx)
+		if traceID != "" || spanID != "" {
+			if fields == nil {
+				fields = make(map[string]interface{}, 2)
+			}
+			if traceID != "" {
+				fields[TraceIDField] = traceID
+			}
+			if spanID != "" {
+				fields[SpanIDField] = spanID
+			}
+		}
+	}
+
+	if fields == nil {
+		return l
+	}
+
+	return l.WithFields(fields)
+}
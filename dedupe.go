@@ -0,0 +1,126 @@
+package log
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// Fields set on error messages when fingerprinting or deduplication is turned on
+const (
+	// FingerprintField is the field the error fingerprint is set in
+	FingerprintField = "fingerprint"
+	// RepeatField is the field the repeated error summary carries the number of repeats in
+	RepeatField = "repeat"
+)
+
+// maxDedupeFingerprints limits how many different errors are tracked at once, errors past it are not suppressed
+const maxDedupeFingerprints = 10000
+
+// fingerprint identifies an error by its type and the top frame of its stack trace,
+// or the code that logged it if the error has no stack trace
+func fingerprint(err error) string {
+	var top string
+	if err, ok := err.(stackTracer); ok && len(err.StackTrace()) != 0 {
+		top = fmt.Sprintf("%+v", err.StackTrace()[0])
+	} else if pc, ok := callerPC(); ok {
+		info := lookupPC(pc)
+		top = fmt.Sprintf("%s\n\t%s:%d", info.function, info.file, info.line)
+	}
+
+	hash := fnv.New64a()
+	_, _ = fmt.Fprintf(hash, "%T\n%s", err, top)
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
+
+// repeated is an error seen during its dedupe window
+type repeated struct {
+	count  int
+	last   error
+	stop   func() bool
+	report func(count int, last error)
+}
+
+// deduper suppresses errors with the same fingerprint that repeat within the window
+type deduper struct {
+	mutex  sync.Mutex
+	window time.Duration
+	clock  Clock
+	seen   map[string]*repeated
+}
+
+func newDeduper(window time.Duration, clock Clock) *deduper {
+	if window <= 0 {
+		return nil
+	}
+
+	return &deduper{window: window, clock: clock, seen: map[string]*repeated{}}
+}
+
+// allow is true for the first error with the key in a window, later ones are counted and
+// report is called with the count and the last of them once the window ends
+func (d *deduper) allow(key string, err error, report func(count int, last error)) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if seen, ok := d.seen[key]; ok {
+		seen.count++
+		seen.last = err
+		return false
+	}
+
+	if len(d.seen) >= maxDedupeFingerprints {
+		return true
+	}
+
+	seen := &repeated{report: report}
+	seen.stop = afterFunc(d.clock, d.window, func() { d.expire(key, seen) })
+	d.seen[key] = seen
+	return true
+}
+
+func (d *deduper) expire(key string, seen *repeated) {
+	d.mutex.Lock()
+	if d.seen[key] != seen {
+		d.mutex.Unlock()
+		return
+	}
+	delete(d.seen, key)
+	count, last := seen.count, seen.last
+	d.mutex.Unlock()
+
+	if count != 0 {
+		seen.report(count, last)
+	}
+}
+
+// flush reports the repeats of every error still in its window
+func (d *deduper) flush() {
+	d.mutex.Lock()
+	seen := d.seen
+	d.seen = map[string]*repeated{}
+	d.mutex.Unlock()
+
+	for _, s := range seen {
+		s.stop()
+		if s.count != 0 {
+			s.report(s.count, s.last)
+		}
+	}
+}
+
+// dedupe checks if the error should be logged, repeats within Settings.DedupeWindow are suppressed and
+// logged as one message with the repeat count and the text of the last repeat once the window ends.
+// Fatal errors and errors below the level are never suppressed.
+func (l logger) dedupe(err error, fingerprint string, level Level) bool {
+	if l.deduper == nil || level == FATAL || !l.enabled(level) {
+		return true
+	}
+
+	key := fmt.Sprintf("%d:%s", level.Severity, fingerprint)
+	return l.deduper.allow(key, err, func(count int, last error) {
+		fields := map[string]interface{}{FingerprintField: fingerprint, RepeatField: count}
+		l.printFieldsLog(fmt.Sprintf("Error repeated %d more times in %s: %s", count, l.settings.DedupeWindow, last.Error()), level, fields)
+	})
+}
@@ -0,0 +1,70 @@
+package log
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDedupe(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      func(l ILog, clock *fakeClock)
+		expected []string
+	}{
+		{
+			name: "summary has the last error",
+			log: func(l ILog, clock *fakeClock) {
+				for _, text := range []string{"first", "second", "third"} {
+					l.Error(errors.New(text), "failed")
+				}
+				clock.Advance(time.Minute)
+			},
+			expected: []string{"failed\nError: first", "Error repeated 2 more times in 1m0s: third"},
+		},
+		{
+			name: "errors below the level are not counted",
+			log: func(l ILog, clock *fakeClock) {
+				l.SetLevel(FATAL)
+				for i := 0; i < 3; i++ {
+					l.Error(errTest, "hidden")
+				}
+				l.SetLevel(ERROR)
+				l.Error(errTest, "shown")
+				clock.Advance(time.Minute)
+			},
+			expected: []string{"shown"},
+		},
+		{
+			name: "new window after the summary",
+			log: func(l ILog, clock *fakeClock) {
+				for i := 0; i < 2; i++ {
+					l.Error(errTest, "failed")
+				}
+				clock.Advance(time.Minute)
+				l.Error(errTest, "failed")
+			},
+			expected: []string{"failed", "Error repeated 1 more times", "failed"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clock := newFakeClock()
+			m := NewMemoryLogger(Settings{MinLogLevel: ERROR, DedupeWindow: time.Minute, Clock: clock})
+
+			test.log(m, clock)
+
+			messages := m.Messages()
+			if len(messages) != len(test.expected) {
+				t.Fatalf("logged %d messages, expected %d", len(messages), len(test.expected))
+			}
+			for i, message := range messages {
+				if !strings.HasPrefix(message.Text, test.expected[i]) {
+					t.Errorf("message %d is %q, expected it to start with %q", i, message.Text, test.expected[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// defaultHolder wraps the default logger so it can be kept in an atomic.Value whatever its type is
+type defaultHolder struct {
+	log ILog
+}
+
+var (
+	defaultLog     atomic.Value
+	defaultConsole ILog
+	defaultOnce    sync.Once
+)
+
+// consoleLogger is the default logger until SetDefault is called, it only writes to the console
+func consoleLogger() ILog {
+	defaultOnce.Do(func() {
+		defaultConsole = Create(Settings{MinLogLevel: INFO, LogToConsole: true})
+	})
+	return defaultConsole
+}
+
+// SetDefault sets the logger used by the package level functions, nil goes back to the console only logger.
+// The previous logger is not closed.
+func SetDefault(l ILog) {
+	if l == nil {
+		l = consoleLogger()
+	}
+	defaultLog.Store(defaultHolder{log: l})
+}
+
+// Default gets the logger used by the package level functions, until SetDefault is called it is a
+// logger that writes info level and above to the console
+func Default() ILog {
+	if holder, ok := defaultLog.Load().(defaultHolder); ok {
+		return holder.log
+	}
+	return consoleLogger()
+}
+
+// Trace print trace level message with the default logger
+func Trace(v ...interface{}) {
+	Default().Trace(v...)
+}
+
+// Tracef print formatted trace level message with the default logger
+func Tracef(format string, v ...interface{}) {
+	Default().Tracef(format, v...)
+}
+
+// Debug print debug level message with the default logger
+func Debug(v ...interface{}) {
+	Default().Debug(v...)
+}
+
+// Debugf print formatted debug level message with the default logger
+func Debugf(format string, v ...interface{}) {
+	Default().Debugf(format, v...)
+}
+
+// Info print info level message with the default logger, the same as Print
+func Info(v ...interface{}) {
+	Default().Print(v...)
+}
+
+// Infof print formatted info level message with the default logger, the same as Printf
+func Infof(format string, v ...interface{}) {
+	Default().Printf(format, v...)
+}
+
+// Print print info level message with the default logger
+func Print(v ...interface{}) {
+	Default().Print(v...)
+}
+
+// Printf print formatted info level message with the default logger
+func Printf(format string, v ...interface{}) {
+	Default().Printf(format, v...)
+}
+
+// Warn print warning level message with the default logger
+func Warn(v ...interface{}) {
+	Default().Warn(v...)
+}
+
+// Warnf print formatted warning level message with the default logger
+func Warnf(format string, v ...interface{}) {
+	Default().Warnf(format, v...)
+}
+
+// Error print error level message with the default logger
+func Error(err error, v ...interface{}) {
+	Default().Error(err, v...)
+}
+
+// Errorf print formatted error level message with the default logger
+func Errorf(err error, format string, v ...interface{}) {
+	Default().Errorf(err, format, v...)
+}
+
+// Fatal print fatal level message with the default logger and panic
+func Fatal(err error, v ...interface{}) {
+	Default().Fatal(err, v...)
+}
+
+// Fatalf print formatted fatal level message with the default logger and panic
+func Fatalf(err error, format string, v ...interface{}) {
+	Default().Fatalf(err, format, v...)
+}
+
+// Log print a message at the level with the default logger
+func Log(level Level, v ...interface{}) {
+	Default().Log(level, v...)
+}
+
+// Logf print a formatted message at the level with the default logger
+func Logf(level Level, format string, v ...interface{}) {
+	Default().Logf(level, format, v...)
+}
+
+// WithField gets the default logger with the field added to every message
+func WithField(key string, value interface{}) ILog {
+	return Default().WithField(key, value)
+}
+
+// With gets the default logger with the typed fields added to every message
+func With(fields ...Field) ILog {
+	return Default().With(fields...)
+}
+
+// WithFields gets the default logger with the fields added to every message
+func WithFields(fields map[string]interface{}) ILog {
+	return Default().WithFields(fields)
+}
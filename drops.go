@@ -0,0 +1,125 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DroppedField is the field the dropped message summary carries the total count in
+const DroppedField = "dropped"
+
+// dropCounter counts the messages dropped since the last summary was reported
+type dropCounter struct {
+	mutex     sync.Mutex
+	reasons   map[string]int
+	total     int
+	threshold int
+	interval  time.Duration
+	clock     Clock
+	stop      func() bool
+	report    func(total int, reasons map[string]int)
+}
+
+func newDropCounter(interval time.Duration, threshold int, clock Clock, report func(total int, reasons map[string]int)) *dropCounter {
+	if interval <= 0 && threshold <= 0 {
+		return nil
+	}
+
+	return &dropCounter{
+		reasons:   map[string]int{},
+		threshold: threshold,
+		interval:  interval,
+		clock:     clock,
+		report:    report,
+	}
+}
+
+// record a dropped message, the summary is reported once the threshold is hit or once the interval
+// has passed since the first drop that has not been reported
+func (d *dropCounter) record(reason string) {
+	d.mutex.Lock()
+	d.reasons[reason]++
+	d.total++
+
+	if d.threshold > 0 && d.total >= d.threshold {
+		d.mutex.Unlock()
+		d.flush()
+		return
+	}
+
+	if d.stop == nil && d.interval > 0 {
+		d.stop = afterFunc(d.clock, d.interval, d.flush)
+	}
+	d.mutex.Unlock()
+}
+
+func (d *dropCounter) flush() {
+	d.mutex.Lock()
+	if d.stop != nil {
+		d.stop()
+		d.stop = nil
+	}
+
+	total, reasons := d.total, d.reasons
+	d.total = 0
+	d.reasons = map[string]int{}
+	d.mutex.Unlock()
+
+	if total != 0 {
+		d.report(total, reasons)
+	}
+}
+
+// dropped records that a message was not logged for the given reason
+func (l logger) dropped(reason string) {
+	atomic.AddUint64(&l.stats.dropped, 1)
+	if l.drops != nil {
+		l.drops.record(reason)
+	}
+}
+
+// reportDropped logs the dropped message summary whatever the logger's level is. It is written straight to the
+// console and the publishers, skipping the sampler, queue, batcher and async console, so whatever dropped the
+// messages can not drop the summary as well and report yet another drop.
+func (l logger) reportDropped(total int, reasons map[string]int) {
+	names := make([]string, 0, len(reasons))
+	for reason := range reasons {
+		names = append(names, reason)
+	}
+	sort.Strings(names)
+
+	details := make([]string, 0, len(names))
+	fields := map[string]interface{}{DroppedField: total}
+	for _, reason := range names {
+		details = append(details, fmt.Sprintf("%s: %d", reason, reasons[reason]))
+		fields[DroppedField+"."+reason] = reasons[reason]
+	}
+
+	message := l.newMessage(fmt.Sprintf("Dropped %d log messages (%s)", total, strings.Join(details, ", ")), WARNING)
+	message.Fields = l.normalizeFields(fields)
+
+	l.stats.logged(message.Level)
+	l.tails.add(message)
+	l.memory.add(message, message.Level)
+
+	messageBites, err := l.encode(message)
+	if err != nil {
+		l.internal().Printf("Unable to encode log message: %s", err.Error())
+	}
+
+	printed := false
+	if l.settings.LogToConsole {
+		l.console.writeNow(l.formatConsole(message, messageBites))
+		printed = true
+	}
+
+	// while paused the summary is held even if the pause buffer is full
+	e := entry{message: message, data: messageBites, printed: printed}
+	if messageBites != nil && !l.pause.force(e) {
+		l.publish(e)
+	}
+}
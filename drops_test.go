@@ -0,0 +1,94 @@
+package log
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// stuckPublisher blocks the first publish until it is released, the rest are recorded right away
+type stuckPublisher struct {
+	mutex    sync.Mutex
+	started  chan struct{}
+	release  chan struct{}
+	calls    int32
+	messages []Message
+}
+
+func newStuckPublisher() *stuckPublisher {
+	return &stuckPublisher{started: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (p *stuckPublisher) Publish(messageBites []byte) error {
+	if atomic.AddInt32(&p.calls, 1) == 1 {
+		close(p.started)
+		<-p.release
+	}
+
+	var message Message
+	if err := json.Unmarshal(messageBites, &message); err != nil {
+		return err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.messages = append(p.messages, message)
+	return nil
+}
+
+func (p *stuckPublisher) summaries() (count int, dropped int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, message := range p.messages {
+		if total, ok := message.Fields[DroppedField].(float64); ok {
+			count++
+			dropped += int(total)
+		}
+	}
+	return count, dropped
+}
+
+func TestDropSummaryBypassesFullQueue(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy OverflowPolicy
+	}{
+		{name: "drop newest", policy: DropNewest},
+		{name: "drop oldest", policy: DropOldest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			publisher := newStuckPublisher()
+			m := NewMemoryLogger(Settings{
+				MinLogLevel:         ERROR,
+				Async:               true,
+				BufferSize:          1,
+				OverflowPolicy:      test.policy,
+				DropReportThreshold: 1,
+				CustomPublishers:    []CustomPublisher{{Name: "stuck", Publisher: publisher}},
+			})
+
+			m.Error(nil, "first")
+			<-publisher.started
+			for i := 0; i < 10; i++ {
+				m.Error(nil, "queued")
+			}
+
+			count, dropped := publisher.summaries()
+			if count == 0 {
+				t.Fatal("no drop summaries were published while the queue was full")
+			}
+
+			close(publisher.release)
+			if err := m.Close(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if expected := int(GetStats(m).Dropped); dropped != expected || count != expected {
+				t.Errorf("%d summaries reported %d dropped messages, expected %d of each", count, dropped, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package log
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// LevelTextCase is the case the level text is sent to the publishers in
+type LevelTextCase string
+
+const (
+	// LevelTextAsIs sends the level text unchanged e.g. "Error"
+	LevelTextAsIs LevelTextCase = ""
+	// LevelTextLower sends the level text in lower case e.g. "error"
+	LevelTextLower LevelTextCase = "lower"
+	// LevelTextUpper sends the level text in upper case e.g. "ERROR"
+	LevelTextUpper LevelTextCase = "upper"
+)
+
+func (c LevelTextCase) apply(text string) string {
+	switch c {
+	case LevelTextLower:
+		return strings.ToLower(text)
+	case LevelTextUpper:
+		return strings.ToUpper(text)
+	}
+	return text
+}
+
+// encode the message as it is sent to the publishers
+func (l logger) encode(message Message) ([]byte, error) {
+	message.Level.Text = l.settings.LevelTextCase.apply(message.Level.Text)
+	if l.settings.FieldNamespace != "" && len(message.Fields) != 0 {
+		message.Fields = map[string]interface{}{l.settings.FieldNamespace: message.Fields}
+	}
+	return json.Marshal(message)
+}
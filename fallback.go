@@ -0,0 +1,69 @@
+package log
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+// deadPublisherFailures is the number of publishes in a row a publisher has to fail before it is treated as dead
+const deadPublisherFailures = 3
+
+// stderrFallback writes messages to stderr while none of the publishers are working
+type stderrFallback struct {
+	active  int32
+	console *console
+}
+
+func newStderrFallback() *stderrFallback {
+	return &stderrFallback{console: newConsole(os.Stderr)}
+}
+
+// write the message to stderr, the message is not written again if it was already printed to the console
+func (f *stderrFallback) write(message Message, printed bool) {
+	if f == nil {
+		return
+	}
+
+	if atomic.CompareAndSwapInt32(&f.active, 0, 1) {
+		f.console.write("WARNING: none of the log publishers are working, logs are being written to stderr")
+	}
+
+	if !printed {
+		f.console.write(message.String())
+	}
+}
+
+// recover stops writing to stderr once a publisher is working again
+func (f *stderrFallback) recover() {
+	if f != nil && atomic.CompareAndSwapInt32(&f.active, 1, 0) {
+		f.console.write("WARNING: the log publishers are working again, logs are no longer written to stderr")
+	}
+}
+
+// publishersDead is true once every publisher that is turned on has failed deadPublisherFailures
+// publishes in a row or is being skipped after it was blocked
+func (l logger) publishersDead() bool {
+	for _, s := range l.sinks {
+		if !s.enabled() {
+			continue
+		}
+
+		if atomic.LoadUint64(&s.failing) < deadPublisherFailures && !s.abandoned() {
+			return false
+		}
+	}
+	return true
+}
+
+// publishFailed counts the failed publishes to the sink
+func (l logger) publishFailed(s *sink, count int) {
+	atomic.AddUint64(&s.failed, uint64(count))
+	atomic.AddUint64(&s.failing, uint64(count))
+}
+
+// publishSucceeded counts the publishes to the sink and stops the stderr fallback
+func (l logger) publishSucceeded(s *sink, count int) {
+	atomic.AddUint64(&s.published, uint64(count))
+	atomic.StoreUint64(&s.failing, 0)
+	l.fallback.recover()
+}
@@ -0,0 +1,102 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// switchPublisher fails every publish while it is down
+type switchPublisher struct {
+	down int32
+}
+
+func (p *switchPublisher) Publish([]byte) error {
+	if atomic.LoadInt32(&p.down) != 0 {
+		return errors.New("publisher is down")
+	}
+	return nil
+}
+
+func (p *switchPublisher) set(down bool) {
+	if down {
+		atomic.StoreInt32(&p.down, 1)
+	} else {
+		atomic.StoreInt32(&p.down, 0)
+	}
+}
+
+func TestStderrFallback(t *testing.T) {
+	tests := []struct {
+		name string
+		// steps are the state of each publisher for every message logged, true is down
+		steps    [][]bool
+		expected []string
+	}{
+		{
+			name:  "transient failure",
+			steps: [][]bool{{true}, {false}, {true}, {true}},
+		},
+		{
+			name:     "dead publisher",
+			steps:    [][]bool{{true}, {true}, {true}, {true}},
+			expected: []string{"none of the log publishers are working", "message 3", "message 4"},
+		},
+		{
+			name:  "one of two dead",
+			steps: [][]bool{{true, false}, {true, false}, {true, false}, {true, false}},
+		},
+		{
+			name:     "both dead",
+			steps:    [][]bool{{true, true}, {true, true}, {true, true}},
+			expected: []string{"none of the log publishers are working", "message 3"},
+		},
+		{
+			name:     "recovers",
+			steps:    [][]bool{{true}, {true}, {true}, {false}, {true}},
+			expected: []string{"none of the log publishers are working", "message 3", "working again"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var publishers []*switchPublisher
+			var custom []CustomPublisher
+			for range test.steps[0] {
+				publisher := &switchPublisher{}
+				publishers = append(publishers, publisher)
+				custom = append(custom, CustomPublisher{Publisher: publisher})
+			}
+
+			l, err := create(Settings{MinLogLevel: INFO, CustomPublishers: custom, OnPublishError: func(error, Message) {}}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var output bytes.Buffer
+			l.fallback = &stderrFallback{console: newConsole(&output)}
+			for i, step := range test.steps {
+				for j, down := range step {
+					publishers[j].set(down)
+				}
+				l.Printf("message %d", i+1)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+			if output.Len() == 0 {
+				lines = nil
+			}
+
+			if len(lines) != len(test.expected) {
+				t.Fatalf("wrote %q to stderr, expected %d lines", output.String(), len(test.expected))
+			}
+			for i, line := range lines {
+				if !strings.Contains(line, test.expected[i]) {
+					t.Errorf("line %d is %q, expected it to contain %q", i, line, test.expected[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Field is a structured field with a typed value
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Int creates an integer field
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Float creates a floating point field
+func Float(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool creates a boolean field
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Duration creates a duration field, it is sent as text such as "1.5s"
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: durationValue(value)}
+}
+
+// Bytes creates a byte count field, it is shown on the console in readable units such as "1.5 MiB"
+// and sent as the number of bytes
+func Bytes(key string, value int64) Field {
+	return Field{Key: key, Value: ByteSize(value)}
+}
+
+// FieldMap creates the fields of a message from typed fields
+func FieldMap(fields ...Field) map[string]interface{} {
+	result := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		result[field.Key] = field.Value
+	}
+	return result
+}
+
+type durationValue time.Duration
+
+func (d durationValue) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalJSON sends the duration as text
+func (d durationValue) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// ByteSize is a number of bytes
+type ByteSize int64
+
+// String shows the size in readable units
+func (b ByteSize) String() string {
+	const unit = 1024
+	if b < unit && b > -unit {
+		return fmt.Sprintf("%d B", int64(b))
+	}
+
+	value, exponent := float64(b)/unit, 0
+	for (value >= unit || value <= -unit) && exponent < 5 {
+		value /= unit
+		exponent++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", value, "KMGTPE"[exponent])
+}
@@ -0,0 +1,162 @@
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultNilFieldValue is how nil field values are rendered when Settings.NilFieldValue is not set
+const DefaultNilFieldValue = "<nil>"
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+// fieldValue converts a field value into the value that is printed and serialized,
+// errors are rendered as their message, nils as the nil marker and struct fields tagged with `log:"redact"` are masked
+func (l logger) fieldValue(value interface{}) interface{} {
+	if isNil(value) {
+		if l.settings.NilFieldValue != "" {
+			return l.settings.NilFieldValue
+		}
+		return DefaultNilFieldValue
+	}
+
+	if err, ok := value.(error); ok {
+		return err.Error()
+	}
+
+	if redacted, ok := redact(value); ok {
+		return redacted
+	}
+
+	return value
+}
+
+func (l logger) normalizeFields(fields map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		result[key] = l.fieldValue(value)
+	}
+
+	return result
+}
+
+// withField copies the fields adding the new field
+func withField(fields map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(fields)+1)
+	for k, v := range fields {
+		result[k] = v
+	}
+	result[key] = value
+	return result
+}
+
+// mergeFields combines the fields, fields in extra replace the ones in base.
+// If either is empty the other is returned as is so the result must not be changed
+func mergeFields(base map[string]interface{}, extra map[string]interface{}) map[string]interface{} {
+	if len(base) == 0 {
+		return extra
+	}
+
+	if len(extra) == 0 {
+		return base
+	}
+
+	result := make(map[string]interface{}, len(base)+len(extra))
+	for key, value := range base {
+		result[key] = value
+	}
+	for key, value := range extra {
+		result[key] = value
+	}
+	return result
+}
+
+// WithField creates a logger that adds the field to every message, the logger it was created from is unchanged
+func (l logger) WithField(key string, value interface{}) ILog {
+	l.fields = withField(l.fields, key, value)
+	return l
+}
+
+// With creates a logger that adds the typed fields to every message, e.g. l.With(Int("attempt", 2), Duration("took", d))
+func (l logger) With(fields ...Field) ILog {
+	return l.WithFields(FieldMap(fields...))
+}
+
+// WithFields creates a logger that adds the fields to every message, the logger it was created from is unchanged
+func (l logger) WithFields(fields map[string]interface{}) ILog {
+	// always copy so later changes to the caller's map do not change the logger
+	result := make(map[string]interface{}, len(l.fields)+len(fields))
+	for key, value := range l.fields {
+		result[key] = value
+	}
+	for key, value := range fields {
+		result[key] = value
+	}
+
+	l.fields = result
+	return l
+}
+
+// formatFields renders the fields as sorted key=value pairs
+func formatFields(fields map[string]interface{}) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	for i, key := range keys {
+		if i != 0 {
+			builder.WriteString(" ")
+		}
+
+		value := fmt.Sprint(fields[key])
+		if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+			value = strconv.Quote(value)
+		}
+
+		builder.WriteString(key)
+		builder.WriteString("=")
+		builder.WriteString(value)
+	}
+
+	return builder.String()
+}
+
+// Child creates a logger for a part of the service, the name is added to the end of the service name
+// after a dot and the fields are added to every message
+func (l logger) Child(name string, fields ...Field) ILog {
+	l = l.withOwnBreadcrumbs()
+	if name != "" {
+		if l.settings.ServiceName != "" {
+			name = l.settings.ServiceName + "." + name
+		}
+		l.settings.ServiceName = name
+	}
+
+	if len(fields) == 0 {
+		return l
+	}
+
+	return l.WithFields(FieldMap(fields...))
+}
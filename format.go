@@ -0,0 +1,145 @@
+package log
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ConsoleFormat is how messages are written to the console
+type ConsoleFormat string
+
+const (
+	// TextFormat writes messages as readable text, it is the default
+	TextFormat ConsoleFormat = "text"
+	// LogfmtFormat writes messages as logfmt key=value pairs
+	LogfmtFormat ConsoleFormat = "logfmt"
+	// JSONFormat writes the same JSON that is sent to the publishers, one object per line
+	JSONFormat ConsoleFormat = "json"
+	// ColorFormat writes messages as text colored by level for terminals
+	ColorFormat ConsoleFormat = "color"
+)
+
+// ANSI colors used by ColorFormat
+const (
+	colorReset  = "\x1b[0m"
+	colorGray   = "\x1b[90m"
+	colorBlue   = "\x1b[34m"
+	colorYellow = "\x1b[33m"
+	colorRed    = "\x1b[31m"
+	colorPurple = "\x1b[35m"
+)
+
+func levelColor(level Level) string {
+	switch {
+	case level.Severity >= FATAL.Severity:
+		return colorPurple
+	case level.Severity >= ERROR.Severity:
+		return colorRed
+	case level.Severity >= WARNING.Severity:
+		return colorYellow
+	case level.Severity >= INFO.Severity:
+		return colorBlue
+	default:
+		return colorGray
+	}
+}
+
+// formatConsole formats the message for the console in the configured format,
+// data is the encoded message and falls back to text if the message could not be encoded
+func (l logger) formatConsole(message Message, data []byte) string {
+	if l.settings.ConsoleFormatter != nil {
+		return l.settings.ConsoleFormatter(message)
+	}
+
+	switch l.settings.ConsoleFormat {
+	case ColorFormat:
+		return levelColor(message.Level) + strings.TrimSuffix(message.Render(l.timeFormat()), "\n") + colorReset
+	case LogfmtFormat:
+		return formatLogfmt(message)
+	case JSONFormat:
+		if data != nil {
+			return string(data)
+		}
+		return message.Render(l.timeFormat())
+	default:
+		return message.Render(l.timeFormat())
+	}
+}
+
+// formatLogfmt formats the message as logfmt, the text and any values with spaces, quotes or
+// newlines are quoted and escaped
+func formatLogfmt(message Message) string {
+	var builder strings.Builder
+	writePair := func(key string, value interface{}) {
+		if builder.Len() != 0 {
+			builder.WriteString(" ")
+		}
+		builder.WriteString(logfmtKey(key))
+		builder.WriteString("=")
+		builder.WriteString(logfmtValue(value))
+	}
+
+	writePair("time", time.Unix(0, message.Time*int64(time.Millisecond)).Format(time.RFC3339Nano))
+	writePair("level", strings.ToLower(message.Level.Text))
+	writePair("service", message.ServiceName)
+	writePair("host", message.Hostname)
+	writePair("msg", strings.TrimSuffix(message.Text, "\n"))
+
+	if message.ID != "" {
+		writePair("id", message.ID)
+	}
+
+	if message.Package != "" {
+		writePair("package", message.Package)
+	}
+
+	if message.File != "" {
+		writePair("caller", fmt.Sprintf("%s:%d", filepath.Base(message.File), message.Line))
+		writePair("func", message.Func)
+	}
+
+	keys := make([]string, 0, len(message.Fields))
+	for key := range message.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		writePair(key, message.Fields[key])
+	}
+
+	return builder.String()
+}
+
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == unicode.ReplacementChar {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+func logfmtValue(value interface{}) string {
+	text := fmt.Sprint(value)
+	if text == "" {
+		return `""`
+	}
+
+	if strings.IndexFunc(text, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || r == '\\' || !unicode.IsPrint(r)
+	}) != -1 {
+		return strconv.Quote(text)
+	}
+
+	return text
+}
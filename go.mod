@@ -7,4 +7,6 @@ require (
 	github.com/cjburchell/settings-go v1.1.20
 	github.com/cjburchell/tools-go v1.0.10
 	github.com/pkg/errors v0.9.1
+	golang.org/x/sys v0.0.0-20200331124033-c3d80250170d
+	gopkg.in/yaml.v2 v2.3.0
 )
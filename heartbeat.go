@@ -0,0 +1,37 @@
+package log
+
+import (
+	"runtime"
+	"time"
+)
+
+// heartbeat logs an info message on every interval until the logger is closed, it is skipped while the
+// logger's level is above info
+func (l logger) heartbeat(interval time.Duration, done <-chan struct{}) {
+	started := l.clock().Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if INFO.Severity < l.minLevel().Severity {
+				continue
+			}
+
+			var memory runtime.MemStats
+			runtime.ReadMemStats(&memory)
+
+			uptime := l.clock().Now().Sub(started).Round(time.Second)
+			message := l.newMessage("Heartbeat: service alive for "+uptime.String(), INFO)
+			message.Fields = map[string]interface{}{
+				"uptime":     uptime.String(),
+				"goroutines": runtime.NumGoroutine(),
+				"heapAlloc":  memory.HeapAlloc,
+			}
+			l.dispatch(message)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package log
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// DefaultMaxHexDumpSize is the number of bytes HexDump logs when Settings.MaxHexDumpSize is not set
+const DefaultMaxHexDumpSize = 4096
+
+// Fields added to hex dump messages
+const (
+	HexDumpDataField      = "data"
+	HexDumpSizeField      = "size"
+	HexDumpTruncatedField = "truncated"
+)
+
+// hexDumper is implemented by the loggers in this package so HexDump can use their settings
+type hexDumper interface {
+	hexDump(level Level, label string, data []byte)
+}
+
+// HexDump logs the data as a hex dump showing the offset, hex and ASCII of each line.
+// The data is also sent base64 encoded in the data field. Only the first Settings.MaxHexDumpSize bytes are logged,
+// DefaultMaxHexDumpSize for loggers from other packages.
+func HexDump(l ILog, level Level, label string, data []byte) {
+	if d, ok := unwrap(l).(hexDumper); ok {
+		d.hexDump(level, label, data)
+		return
+	}
+
+	text, fields := hexDumpMessage(DefaultMaxHexDumpSize, label, data)
+	l.WithFields(fields).Log(level, text)
+}
+
+func (l logger) hexDump(level Level, label string, data []byte) {
+	text, fields := hexDumpMessage(l.settings.MaxHexDumpSize, label, data)
+	l.printFieldsLog(text, level, fields)
+}
+
+// hexDumpMessage gets the text and fields the data is logged with
+func hexDumpMessage(maxSize int, label string, data []byte) (string, map[string]interface{}) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxHexDumpSize
+	}
+
+	dumped := data
+	if len(dumped) > maxSize {
+		dumped = dumped[:maxSize]
+	}
+
+	text := fmt.Sprintf("%s (%d bytes)\n%s", label, len(data), hex.Dump(dumped))
+	if len(dumped) < len(data) {
+		text += fmt.Sprintf("... %d more bytes\n", len(data)-len(dumped))
+	}
+
+	return text, map[string]interface{}{
+		HexDumpDataField:      base64.StdEncoding.EncodeToString(dumped),
+		HexDumpSizeField:      len(data),
+		HexDumpTruncatedField: len(dumped) < len(data),
+	}
+}
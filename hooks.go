@@ -0,0 +1,82 @@
+package log
+
+import "sync"
+
+// Hook is called with each message before it is printed and published, it can change the message
+// or return false to drop it
+type Hook func(message *Message) bool
+
+// hooks are the hooks shared by a logger and its children
+type hooks struct {
+	mutex sync.RWMutex
+	list  []Hook
+}
+
+func newHooks(list []Hook) *hooks {
+	return &hooks{list: append([]Hook(nil), list...)}
+}
+
+func (h *hooks) snapshot() []Hook {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.list
+}
+
+// hookLogger is implemented by the loggers in this package that run hooks
+type hookLogger interface {
+	addHook(hook Hook)
+}
+
+// AddHook adds a hook to the logger that runs after the hooks already added, it is shared with the logger's children.
+// Loggers from other packages do not run hooks so they are left unchanged.
+func AddHook(l ILog, hook Hook) {
+	if h, ok := unwrap(l).(hookLogger); ok {
+		h.addHook(hook)
+	}
+}
+
+func (l logger) addHook(hook Hook) {
+	if hook == nil {
+		return
+	}
+
+	l.hooks.mutex.Lock()
+	defer l.hooks.mutex.Unlock()
+	// copy so a snapshot being run is not changed
+	l.hooks.list = append(append(make([]Hook, 0, len(l.hooks.list)+1), l.hooks.list...), hook)
+}
+
+// runHooks runs the Settings.Hooks and the added hooks in order, false is returned if a hook dropped the message.
+// A hook that panics is skipped and the message is kept.
+func (l logger) runHooks(message *Message) bool {
+	list := l.hooks.snapshot()
+	if len(list) == 0 {
+		return true
+	}
+
+	// give the hooks their own copy of the fields so they can change them freely
+	fields := make(map[string]interface{}, len(message.Fields))
+	for key, value := range message.Fields {
+		fields[key] = value
+	}
+	message.Fields = fields
+
+	for i, hook := range list {
+		if !l.runHook(i, hook, message) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (l logger) runHook(index int, hook Hook, message *Message) (keep bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			l.internal().Printf("Log hook %d panicked: %v", index, r)
+			keep = true
+		}
+	}()
+
+	return hook(message)
+}
@@ -0,0 +1,79 @@
+package log
+
+import (
+	"bytes"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	tests := []struct {
+		name     string
+		hooks    []Hook
+		added    Hook
+		expected []string
+		internal string
+	}{
+		{
+			name:     "change the text",
+			hooks:    []Hook{func(message *Message) bool { message.Text += " changed"; return true }},
+			expected: []string{"1 changed", "2 changed"},
+		},
+		{
+			name:     "drop",
+			hooks:    []Hook{func(message *Message) bool { return message.Text != "1" }},
+			expected: []string{"2"},
+		},
+		{
+			name:     "added hooks run after the settings hooks",
+			hooks:    []Hook{func(message *Message) bool { message.Text += " a"; return true }},
+			added:    func(message *Message) bool { message.Text += " b"; return true },
+			expected: []string{"1 a b", "2 a b"},
+		},
+		{
+			name: "panic keeps the message",
+			hooks: []Hook{
+				func(message *Message) bool { panic("hook failed") },
+				func(message *Message) bool { message.Text += " next"; return true },
+			},
+			expected: []string{"1 next", "2 next"},
+			internal: "Log hook 0 panicked: hook failed",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var internal bytes.Buffer
+			l, publisher := newRecordLogger(Settings{Hooks: test.hooks, InternalLogger: log.New(&internal, "", 0)})
+			if test.added != nil {
+				AddHook(l, test.added)
+			}
+
+			l.Print("1")
+			l.Print("2")
+
+			if published := publisher.published(); !reflect.DeepEqual(published, test.expected) {
+				t.Errorf("published %v, expected %v", published, test.expected)
+			}
+			if !strings.Contains(internal.String(), test.internal) || (test.internal == "" && internal.Len() != 0) {
+				t.Errorf("internal log is %q, expected %q", internal.String(), test.internal)
+			}
+		})
+	}
+}
+
+func TestHooksDoNotChangeSharedFields(t *testing.T) {
+	l, _ := newRecordLogger(Settings{Hooks: []Hook{func(message *Message) bool {
+		message.Fields["key"] = "changed"
+		return true
+	}}})
+
+	fields := map[string]interface{}{"key": "value"}
+	l.WithFields(fields).Print("1")
+
+	if fields["key"] != "value" {
+		t.Errorf("field is %v, expected value", fields["key"])
+	}
+}
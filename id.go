@@ -0,0 +1,25 @@
+package log
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// NewUUID generates a random (version 4) UUID, it is the default Settings.IDGenerator
+func NewUUID() string {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return ""
+	}
+
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:])
+}
+
+func (l logger) newID() string {
+	if l.settings.IDGenerator != nil {
+		return l.settings.IDGenerator()
+	}
+	return NewUUID()
+}
@@ -0,0 +1,17 @@
+package log
+
+import (
+	"log"
+	"os"
+)
+
+// defaultInternalLogger is used for the logger's own diagnostics when Settings.InternalLogger is not set
+var defaultInternalLogger = log.New(os.Stderr, "", log.LstdFlags)
+
+// internal gets the logger for the logger's own diagnostics such as publisher failures
+func (l logger) internal() *log.Logger {
+	if l.settings.InternalLogger != nil {
+		return l.settings.InternalLogger
+	}
+	return defaultInternalLogger
+}
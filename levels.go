@@ -0,0 +1,95 @@
+package log
+
+import (
+	"strings"
+	"sync"
+)
+
+// LoggerNameField is the field named loggers put their name in
+const LoggerNameField = "logger"
+
+// levels are the minimum levels that can be changed while the logger is running
+type levels struct {
+	mutex      sync.RWMutex
+	global     Level
+	components map[string]Level
+}
+
+func newLevels(global Level, components map[string]Level) *levels {
+	result := &levels{global: global, components: make(map[string]Level, len(components))}
+	for name, level := range components {
+		result.components[name] = level
+	}
+	return result
+}
+
+// get the level for the named logger, a name without its own level uses its parent's e.g. db.pool uses db
+func (v *levels) get(name string) Level {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+
+	for name != "" {
+		if level, ok := v.components[name]; ok {
+			return level
+		}
+
+		dot := strings.LastIndex(name, ".")
+		if dot < 0 {
+			break
+		}
+		name = name[:dot]
+	}
+
+	return v.global
+}
+
+func (v *levels) set(name string, level Level) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if name == "" {
+		v.global = level
+	} else {
+		v.components[name] = level
+	}
+}
+
+// levelFilter is implemented by the loggers in this package that can tell if a level would be logged
+type levelFilter interface {
+	enabled(level Level) bool
+}
+
+// enabled is false if the logger drops messages at the level, loggers from other packages are always enabled
+func enabled(l ILog, level Level) bool {
+	if f, ok := unwrap(l).(levelFilter); ok {
+		return f.enabled(level)
+	}
+	return true
+}
+
+func (l logger) enabled(level Level) bool {
+	return level.Severity >= l.minLevel().Severity
+}
+
+// minLevel is the lowest level the logger logs
+func (l logger) minLevel() Level {
+	return l.levels.get(l.name)
+}
+
+// SetLevel changes the lowest level logged, for a named logger only the level of that name is changed
+func (l logger) SetLevel(level Level) {
+	l.levels.set(l.name, level)
+}
+
+// Named creates a logger for a part of the service whose level can be set on its own with
+// Settings.ComponentLevels or SetLevel. The name of a named logger's children is appended after a dot.
+func (l logger) Named(name string) ILog {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+
+	l = l.withOwnBreadcrumbs()
+	l.name = name
+	l.fields = withField(l.fields, LoggerNameField, name)
+	return l
+}
@@ -0,0 +1,81 @@
+package log
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// lifecycle stops the background goroutines of a logger when it is closed
+type lifecycle struct {
+	once sync.Once
+	done chan struct{}
+	wait sync.WaitGroup
+}
+
+func newLifecycle() *lifecycle {
+	return &lifecycle{done: make(chan struct{})}
+}
+
+// goroutine starts a background goroutine that is waited for on close
+func (c *lifecycle) goroutine(run func(done <-chan struct{})) {
+	c.wait.Add(1)
+	go func() {
+		defer c.wait.Done()
+		run(c.done)
+	}()
+}
+
+// close stops the background goroutines and waits for them to finish, only the first call has any effect
+func (c *lifecycle) close() {
+	c.once.Do(func() {
+		close(c.done)
+		c.wait.Wait()
+	})
+}
+
+// Close stops the logger's background work, publishes the buffered messages and those held by Pause and closes
+// the publishers that need it, it is safe to call more than once
+func (l logger) Close() error {
+	l.lifecycle.close()
+	if l.deduper != nil {
+		l.deduper.flush()
+	}
+	// messages held by Pause are published rather than lost
+	l.Resume()
+	l.flushBatch(true)
+	if l.queue != nil {
+		l.queue.close()
+	}
+	if l.drops != nil {
+		l.drops.flush()
+	}
+	l.console.close()
+
+	var result error
+	for _, s := range l.sinks {
+		if closer, ok := s.publisher.(io.Closer); ok {
+			if err := closer.Close(); err != nil && result == nil {
+				result = err
+			}
+		}
+	}
+
+	return result
+}
+
+// Shutdown closes the logger like Close but gives up waiting once the context is done,
+// the logger keeps closing in the background
+func (l logger) Shutdown(ctx context.Context) error {
+	closed := make(chan error, 1)
+	go func() {
+		closed <- l.Close()
+	}()
+
+	select {
+	case err := <-closed:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,11 +1,12 @@
 package log
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -23,6 +24,8 @@ type Level struct {
 }
 
 var (
+	// TRACE log level
+	TRACE = Level{Text: "Trace", Severity: -1}
 	// DEBUG log level
 	DEBUG = Level{Text: "Debug", Severity: 0}
 	// INFO log level
@@ -43,61 +46,252 @@ type ILog interface {
 	Errorf(err error, format string, v ...interface{})
 	Fatal(err error, v ...interface{})
 	Fatalf(err error, format string, v ...interface{})
+	Trace(v ...interface{})
+	Tracef(format string, v ...interface{})
 	Debug(v ...interface{})
 	Debugf(format string, v ...interface{})
 	Print(v ...interface{})
 	Printf(format string, v ...interface{})
 	GetWriter(level Level) io.Writer
+	Close() error
+	Pause()
+	Resume()
+	WithField(key string, value interface{}) ILog
+	WithFields(fields map[string]interface{}) ILog
+	With(fields ...Field) ILog
+	Flush()
+	WithContext(ctx context.Context) ILog
+	SetLevel(level Level)
+	Named(name string) ILog
+	Child(name string, fields ...Field) ILog
+	Log(level Level, v ...interface{})
+	Logf(level Level, format string, v ...interface{})
+	Shutdown(ctx context.Context) error
 }
 
 type logger struct {
-	publishers []publishers.Publisher
-	settings   Settings
-	hostname   string
+	sinks       []*sink
+	settings    Settings
+	hostname    string
+	breadcrumbs *breadcrumbs
+	console     *console
+	drops       *dropCounter
+	lifecycle   *lifecycle
+	fallback    *stderrFallback
+	pause       *pause
+	tails       *tails
+	budget      *publishers.RetryBudget
+	request     *requestBuffer
+	fields      map[string]interface{}
+	queue       *queue
+	memory      *memory
+	sampler     *sampler
+	batcher     *batcher
+	levels      *levels
+	name        string
+	hooks       *hooks
+	stats       *stats
+	deduper     *deduper
 }
 
+// DefaultAsyncConsoleBufferSize is the number of lines buffered by the async console when Settings.AsyncConsoleBufferSize is not set
+const DefaultAsyncConsoleBufferSize = 1024
+
+// dropConsole is the drop reason for lines that did not fit in the async console buffer
+const dropConsole = "console"
+
 // Create the logger
 func Create(settings Settings) ILog {
+	l, err := create(settings, nil)
+	if err != nil {
+		l.internal().Print(err.Error())
+	}
+	return l
+}
+
+// New creates a logger like Create but returns an error if any of the publishers could not be set up
+func New(settings Settings) (ILog, error) {
+	l, err := create(settings, nil)
+	if err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// create sets up the logger, the logger is usable even if some of the publishers could not be set up
+func create(settings Settings, memory *memory) (logger, error) {
 	var hostname, _ = os.Hostname()
 
 	l := logger{
-		settings: settings,
-		hostname: hostname,
+		settings:    settings,
+		hostname:    hostname,
+		breadcrumbs: newBreadcrumbs(settings.Breadcrumbs),
+		console:     newConsole(consoleWriter(settings)),
+		lifecycle:   newLifecycle(),
+		pause:       newPause(settings.PauseBufferSize, settings.OverflowPolicy),
+		tails:       &tails{},
+		memory:      memory,
+		sampler:     newSampler(settings),
+		batcher:     newBatcher(settings.BatchSize),
+		levels:      newLevels(settings.MinLogLevel, settings.ComponentLevels),
+		hooks:       newHooks(settings.Hooks),
+		stats:       newStats(),
+		deduper:     newDeduper(settings.DedupeWindow, settingsClock(settings)),
 	}
 
-	newPublishers := make([]publishers.Publisher, 0)
+	if settings.AsyncConsole {
+		size := settings.AsyncConsoleBufferSize
+		if size <= 0 {
+			size = DefaultAsyncConsoleBufferSize
+		}
+		l.console = newAsyncConsole(consoleWriter(settings), size, func() { l.dropped(dropConsole) })
+	}
+
+	if settings.RetryBudgetSize > 0 {
+		l.budget = publishers.NewRetryBudget(settings.RetryBudgetSize, settings.RetryBudgetRefill)
+	}
+
+	var setupErrors []string
+	sinks := make([]*sink, 0)
 	if settings.UsePubSub {
 		publisher, err := publishers.SetupPubSub(l.settings.PubSubSettings)
 		if err != nil {
-			log.Printf("Unable to create pub sub publisher %s", err.Error())
+			setupErrors = append(setupErrors, errors.Wrap(err, "unable to create pub sub publisher").Error())
+		} else {
+			if settings.PubSubEnabled != nil {
+				publisher = publishers.Gate(publisher, settings.PubSubEnabled)
+			}
+			sinks = append(sinks, newSink("pubsub", publisher, settings.PubSubMinLogLevel))
+		}
+	}
+
+	if settings.UseEventLog {
+		publisher, err := publishers.SetupEventLog(l.settings.EventLogSettings)
+		if err != nil {
+			setupErrors = append(setupErrors, errors.Wrap(err, "unable to create event log publisher").Error())
 		} else {
-			newPublishers = append(newPublishers, publisher)
+			sinks = append(sinks, newSink("eventlog", publisher, settings.EventLogMinLogLevel))
 		}
 	}
 
 	if settings.UseHTTP {
-		publisher := publishers.SetupHTTP(l.settings.HTTPSettings)
-		newPublishers = append(newPublishers, publisher)
+		httpSettings := l.settings.HTTPSettings
+		if httpSettings.RetryBudget == nil {
+			httpSettings.RetryBudget = l.budget
+		}
+		publisher, err := publishers.NewHTTP(httpSettings)
+		if err != nil {
+			setupErrors = append(setupErrors, errors.Wrap(err, "unable to create http publisher").Error())
+		} else {
+			if settings.HTTPEnabled != nil {
+				publisher = publishers.Gate(publisher, settings.HTTPEnabled)
+			}
+			sinks = append(sinks, newSink("http", publisher, settings.HTTPMinLogLevel))
+		}
 	}
 
-	l.publishers = newPublishers
+	if settings.UseFile {
+		fileSettings := l.settings.FileSettings
+		if fileSettings.Now == nil {
+			fileSettings.Now = l.clock().Now
+		}
+		publisher, err := publishers.SetupFile(fileSettings)
+		if err != nil {
+			setupErrors = append(setupErrors, errors.Wrap(err, "unable to create file publisher").Error())
+		} else {
+			sinks = append(sinks, newSink("file", publisher, settings.FileMinLogLevel))
+		}
+	}
 
-	return l
+	if settings.UseSyslog {
+		publisher, err := publishers.SetupSyslog(l.settings.SyslogSettings)
+		if err != nil {
+			setupErrors = append(setupErrors, errors.Wrap(err, "unable to create syslog publisher").Error())
+		} else {
+			sinks = append(sinks, newSink("syslog", publisher, settings.SyslogMinLogLevel))
+		}
+	}
+
+	if settings.UseJournald {
+		publisher, err := publishers.SetupJournald(l.settings.JournaldSettings)
+		if err != nil {
+			setupErrors = append(setupErrors, errors.Wrap(err, "unable to create journald publisher").Error())
+		} else {
+			sinks = append(sinks, newSink("journald", publisher, settings.JournaldMinLogLevel))
+		}
+	}
+
+	for i, custom := range settings.CustomPublishers {
+		if custom.Publisher == nil {
+			continue
+		}
+
+		name := custom.Name
+		if name == "" {
+			name = fmt.Sprintf("custom%d", i)
+		}
+		sinks = append(sinks, newSink(name, custom.Publisher, custom.MinLogLevel))
+	}
+
+	if settings.SpoolPath != "" {
+		for _, s := range sinks {
+			spool, err := newSpool(settings.SpoolPath, s.name, settings.SpoolMaxSize)
+			if err != nil {
+				setupErrors = append(setupErrors, errors.Wrapf(err, "unable to create spool for %s publisher", s.name).Error())
+				continue
+			}
+			s.spool = spool
+		}
+	}
+
+	l.sinks = sinks
+	if !settings.DisableStderrFallback && (settings.UsePubSub || settings.UseHTTP || settings.UseEventLog || settings.UseFile ||
+		settings.UseSyslog || settings.UseJournald || len(settings.CustomPublishers) != 0) {
+		l.fallback = newStderrFallback()
+	}
+	if settings.Async {
+		l.queue = newQueue(settings.BufferSize, settings.OverflowPolicy)
+		l.start(l.queue, settings.AsyncWorkers)
+	}
+
+	dropReportInterval := settings.DropReportInterval
+	if l.sampler != nil && dropReportInterval <= 0 && settings.DropReportThreshold <= 0 {
+		dropReportInterval = DefaultSampleReportInterval
+	}
+	l.drops = newDropCounter(dropReportInterval, settings.DropReportThreshold, l.clock(), l.reportDropped)
+
+	if l.batcher != nil {
+		interval := settings.BatchInterval
+		if interval <= 0 {
+			interval = DefaultBatchInterval
+		}
+		l.lifecycle.goroutine(func(done <-chan struct{}) {
+			l.flushBatches(interval, done)
+		})
+	}
+
+	if settings.Heartbeat > 0 {
+		l.lifecycle.goroutine(func(done <-chan struct{}) {
+			l.heartbeat(settings.Heartbeat, done)
+		})
+	}
+
+	if len(setupErrors) != 0 {
+		return l, errors.New(strings.Join(setupErrors, "; "))
+	}
+
+	return l, nil
 }
 
 // GetLogLevel gets the log level for input text
 func GetLogLevel(levelText string) Level {
-	var levels = []Level{DEBUG,
-		INFO,
-		WARNING,
-		ERROR,
-		FATAL,
+	if level, ok := lookupLevel(levelText); ok {
+		return level
 	}
 
-	for i := range levels {
-		if levels[i].Text == levelText {
-			return levels[i]
-		}
+	if level, ok := lookupLevelAlias(levelText); ok {
+		return level
 	}
 
 	return INFO
@@ -133,6 +327,15 @@ func (l logger) printErrorLog(err error, msg string, level Level) {
 		return
 	}
 
+	var fields map[string]interface{}
+	if l.settings.FingerprintErrors || l.deduper != nil {
+		id := fingerprint(err)
+		if !l.dedupe(err, id, level) {
+			return
+		}
+		fields = map[string]interface{}{FingerprintField: id}
+	}
+
 	if msg == "" {
 		msg = fmt.Sprintf("Error: %s\n", err.Error())
 	} else {
@@ -142,28 +345,77 @@ func (l logger) printErrorLog(err error, msg string, level Level) {
 	if err, ok := err.(stackTracer); ok {
 		msg += "Stack Trace -----------------------------------------------------------------------------------------\n"
 		for _, f := range err.StackTrace() {
-			msg += fmt.Sprintf("%+v\n", f)
+			msg += truncateLines(fmt.Sprintf("%+v\n", f), l.settings.MaxStackLineWidth)
 		}
 		msg += "-----------------------------------------------------------------------------------------------------"
 	} else {
-		msg += trace.GetStack(2)
+		msg += truncateLines(trace.GetStack(2), l.settings.MaxStackLineWidth)
 	}
 
-	l.printLog(msg, level)
+	l.printFieldsLog(msg, level, fields)
 }
 
-// Fatal print fatal level message
+const ellipsis = "..."
+
+// truncateLines shortens each line in the text to at most width characters, 0 leaves the text unchanged
+func truncateLines(text string, width int) string {
+	if width <= 0 {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		runes := []rune(line)
+		if len(runes) <= width {
+			continue
+		}
+
+		if width <= len(ellipsis) {
+			lines[i] = string(runes[:width])
+		} else {
+			lines[i] = string(runes[:width-len(ellipsis)]) + ellipsis
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Fatal print fatal level message, buffered messages and those held by Pause are published before it panics
 func (l logger) Fatal(err error, v ...interface{}) {
 	l.printErrorLog(err, fmt.Sprint(v...), FATAL)
+	l.Resume()
+	l.Flush()
 	log.Panic(v...)
 }
 
-// Fatalf print formatted fatal level message
+// Fatalf print formatted fatal level message, buffered messages and those held by Pause are published before it panics
 func (l logger) Fatalf(err error, format string, v ...interface{}) {
 	l.printErrorLog(err, fmt.Sprintf(format, v...), FATAL)
+	l.Resume()
+	l.Flush()
 	log.Panicf(format, v...)
 }
 
+// Trace print trace level message
+func (l logger) Trace(v ...interface{}) {
+	l.printLog(fmt.Sprint(v...), TRACE)
+}
+
+// Tracef print formatted trace level message
+func (l logger) Tracef(format string, v ...interface{}) {
+	l.printLog(fmt.Sprintf(format, v...), TRACE)
+}
+
+// Log print a message at the level, use it for levels added with RegisterLevel
+func (l logger) Log(level Level, v ...interface{}) {
+	l.printLog(fmt.Sprint(v...), level)
+}
+
+// Logf print a formatted message at the level
+func (l logger) Logf(level Level, format string, v ...interface{}) {
+	l.printLog(fmt.Sprintf(format, v...), level)
+}
+
 // Debug print debug level message
 func (l logger) Debug(v ...interface{}) {
 	l.printLog(fmt.Sprint(v...), DEBUG)
@@ -186,50 +438,240 @@ func (l logger) Printf(format string, v ...interface{}) {
 
 // Message to be sent to centralized logger
 type Message struct {
-	Text        string `json:"text"`
-	Level       Level  `json:"level"`
-	ServiceName string `json:"serviceName"`
-	Time        int64  `json:"time"`
-	Hostname    string `json:"hostname"`
+	ID          string                 `json:"id,omitempty"`
+	Text        string                 `json:"text"`
+	Level       Level                  `json:"level"`
+	ServiceName string                 `json:"serviceName"`
+	Time        int64                  `json:"time"`
+	Hostname    string                 `json:"hostname"`
+	Package     string                 `json:"package,omitempty"`
+	File        string                 `json:"file,omitempty"`
+	Line        int                    `json:"line,omitempty"`
+	Func        string                 `json:"func,omitempty"`
+	Fields      map[string]interface{} `json:"fields,omitempty"`
 }
 
 func (message Message) String() string {
-	return fmt.Sprintf("[%s] %s %s - %s", message.Level.Text, time.Unix(message.Time/1000, 0).Format("2006-01-02 15:04:05 MST"), message.ServiceName, message.Text)
+	return message.Render(DefaultTimeFormat)
 }
 
-func (l logger) printLog(text string, level Level) {
-	message := Message{
+// Render formats the message as text with the time in the layout
+func (message Message) Render(timeFormat string) string {
+	when := time.Unix(0, message.Time*int64(time.Millisecond)).Format(timeFormat)
+	text := message.Text
+	if len(message.Fields) != 0 {
+		if strings.HasSuffix(text, "\n") {
+			text = fmt.Sprintf("%s %s\n", strings.TrimSuffix(text, "\n"), formatFields(message.Fields))
+		} else {
+			text = fmt.Sprintf("%s %s", text, formatFields(message.Fields))
+		}
+	}
+
+	if message.File != "" {
+		return fmt.Sprintf("[%s] %s %s %s:%d - %s", message.Level.Text, when, message.ServiceName, filepath.Base(message.File), message.Line, text)
+	}
+
+	return fmt.Sprintf("[%s] %s %s - %s", message.Level.Text, when, message.ServiceName, text)
+}
+
+func (l logger) newMessage(text string, level Level) Message {
+	return Message{
+		ID:          l.newID(),
 		Text:        text,
 		Level:       level,
 		ServiceName: l.settings.ServiceName,
-		Time:        time.Now().UnixNano() / 1000000,
+		Time:        l.now(),
 		Hostname:    l.hostname,
 	}
+}
 
-	if level.Severity >= l.settings.MinLogLevel.Severity && l.settings.LogToConsole {
-		if strings.HasSuffix(message.String(), "\n") {
-			fmt.Print(message.String())
-		} else {
-			fmt.Println(message.String())
+func (l logger) printLog(text string, level Level) {
+	l.printFieldsLog(text, level, nil)
+}
+
+func (l logger) printFieldsLog(text string, level Level, fields map[string]interface{}) {
+	// only messages at or above the level count against the sampler and rate limits
+	enabled := level.Severity >= l.minLevel().Severity
+	if enabled && !l.sample(level, text) {
+		return
+	}
+
+	message := l.newMessage(text, level)
+	message.Fields = mergeFields(l.fields, fields)
+
+	if l.settings.IncludePackage {
+		message.Package = callerPackage()
+	}
+
+	if l.settings.IncludeCaller {
+		message.File, message.Line, message.Func = callerLocation(l.settings.CallerSkip)
+	}
+
+	if l.breadcrumbs != nil {
+		if level.Severity >= ERROR.Severity {
+			if crumbs := l.breadcrumbs.snapshot(); len(crumbs) != 0 {
+				message.Fields = withField(message.Fields, BreadcrumbsField, crumbs)
+			}
 		}
+
+		l.breadcrumbs.add(message)
+	}
+
+	// breadcrumbs are kept for messages below the level so errors still show what led up to them
+	if !enabled {
+		return
 	}
 
-	if l.publishers == nil {
+	l.enrich(&message)
+
+	if level.Severity >= FATAL.Severity {
+		l.flushRequest()
+	} else if l.holdRequest(message) {
+		return
+	}
+
+	l.dispatch(message)
+}
+
+// enrich runs the Settings.Enricher on the message, the message gets its own copy of the fields
+// so the enricher can change them freely
+func (l logger) enrich(message *Message) {
+	if l.settings.Enricher == nil {
 		return
 	}
 
-	messageBites, err := json.Marshal(message)
+	fields := make(map[string]interface{}, len(message.Fields))
+	for key, value := range message.Fields {
+		fields[key] = value
+	}
+	message.Fields = fields
+
+	l.settings.Enricher(message)
+}
+
+// dispatch prints the message to the console and sends it to the publishers
+func (l logger) dispatch(message Message) {
+	if !l.runHooks(&message) {
+		return
+	}
+
+	l.stats.logged(message.Level)
+	message.Fields = l.normalizeFields(message.Fields)
+	l.tails.add(message)
+	l.memory.add(message, l.minLevel())
+
+	messageBites, err := l.encode(message)
 	if err != nil {
-		fmt.Println("error:", err)
+		l.internal().Printf("Unable to encode log message: %s", err.Error())
 	}
 
-	for _, publisher := range l.publishers {
-		err = publisher.Publish(messageBites)
+	printed := l.printConsole(message, messageBites)
+
+	l.send(entry{message: message, data: messageBites, printed: printed})
+}
+
+// printConsole prints the message to the console returning true if it was printed,
+// data is the encoded message that is printed in the JSON format
+func (l logger) printConsole(message Message, data []byte) bool {
+	console := l.settings.ConsoleMinLogLevel
+	if message.Level.Severity >= l.minLevel().Severity && l.settings.LogToConsole && (console == Level{} || message.Level.Severity >= console.Severity) {
+		l.console.write(l.formatConsole(message, data))
+		return true
+	}
+	return false
+}
+
+// batchLogger is implemented by the loggers in this package that can log a batch in one pass
+type batchLogger interface {
+	logBatch(messages []Message)
+}
+
+// LogBatch logs a set of messages in one pass. Missing service name, hostname and time are stamped on each message
+// and publishers that implement publishers.BatchPublisher receive the whole batch in a single publish.
+// Loggers from other packages are given the messages one at a time.
+func LogBatch(l ILog, messages []Message) {
+	if b, ok := unwrap(l).(batchLogger); ok {
+		b.logBatch(messages)
+		return
+	}
+
+	for _, message := range messages {
+		l.WithFields(message.Fields).Log(message.Level, message.Text)
+	}
+}
+
+func (l logger) logBatch(messages []Message) {
+	if len(messages) == 0 {
+		return
+	}
+
+	now := l.now()
+	var pkg string
+	if l.settings.IncludePackage {
+		pkg = callerPackage()
+	}
+
+	batch := make([]entry, 0, len(messages))
+	for _, message := range messages {
+		if message.ID == "" {
+			message.ID = l.newID()
+		}
+
+		if message.ServiceName == "" {
+			message.ServiceName = l.settings.ServiceName
+		}
+
+		if message.Hostname == "" {
+			message.Hostname = l.hostname
+		}
+
+		if message.Time == 0 {
+			message.Time = now
+		} else if !l.checkTimestamp(&message, now) {
+			continue
+		}
+
+		if message.Package == "" {
+			message.Package = pkg
+		}
+
+		if message.Level.Severity < l.minLevel().Severity {
+			continue
+		}
+
+		message.Fields = mergeFields(l.fields, message.Fields)
+		l.enrich(&message)
+		if l.holdRequest(message) {
+			continue
+		}
+
+		if !l.runHooks(&message) {
+			continue
+		}
+
+		l.stats.logged(message.Level)
+		message.Fields = l.normalizeFields(message.Fields)
+		l.tails.add(message)
+		l.memory.add(message, l.minLevel())
+
+		messageBites, err := l.encode(message)
 		if err != nil {
-			fmt.Printf("Unable to send log to publisher (%s): %s", err.Error(), message.String())
+			l.internal().Printf("Unable to encode log message: %s", err.Error())
+			l.printConsole(message, nil)
+			continue
+		}
+
+		printed := l.printConsole(message, messageBites)
+
+		e := entry{message: message, data: messageBites, printed: printed}
+		if !l.hold(e) {
+			batch = append(batch, e)
 		}
 	}
 
+	if len(batch) != 0 {
+		l.deliver(batch...)
+	}
 }
 
 func (l logger) GetWriter(level Level) io.Writer {
@@ -242,7 +684,14 @@ type Writer struct {
 	logger logger
 }
 
+// Write logs each non empty line in p as its own message without the line ending
 func (w Writer) Write(p []byte) (n int, err error) {
-	w.logger.printLog(string(p), w.Level)
+	for _, line := range strings.Split(string(p), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line != "" {
+			w.logger.printLog(line, w.Level)
+		}
+	}
+
 	return len(p), nil
 }
@@ -0,0 +1,75 @@
+package log
+
+import "sync"
+
+// memory records messages for a MemoryLogger
+type memory struct {
+	mutex    sync.Mutex
+	messages []Message
+}
+
+func (m *memory) add(message Message, minLevel Level) {
+	if m == nil || message.Level.Severity < minLevel.Severity {
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.messages = append(m.messages, message)
+}
+
+// MemoryLogger is a logger that records the messages at or above its level so tests can check what was logged
+type MemoryLogger struct {
+	ILog
+	memory *memory
+}
+
+// NewMemoryLogger creates a MemoryLogger, the settings are used as they are by Create so publishers and the console
+// are only used if they are configured
+func NewMemoryLogger(settings Settings) *MemoryLogger {
+	recorded := &memory{}
+	l, err := create(settings, recorded)
+	if err != nil {
+		l.internal().Print(err.Error())
+	}
+	return &MemoryLogger{ILog: l, memory: recorded}
+}
+
+// Messages gets a copy of the recorded messages oldest first
+func (m *MemoryLogger) Messages() []Message {
+	m.memory.mutex.Lock()
+	defer m.memory.mutex.Unlock()
+	return append([]Message(nil), m.memory.messages...)
+}
+
+// Reset removes the recorded messages
+func (m *MemoryLogger) Reset() {
+	m.memory.mutex.Lock()
+	defer m.memory.mutex.Unlock()
+	m.memory.messages = nil
+}
+
+// logWrapper is implemented by the loggers in this package that wrap another logger
+type logWrapper interface {
+	unwrapLog() ILog
+}
+
+// unwrap gets the logger underneath any wrappers so its optional interfaces can be checked,
+// loggers that do not wrap another logger are returned as they are
+func unwrap(l ILog) ILog {
+	for {
+		w, ok := l.(logWrapper)
+		if !ok {
+			return l
+		}
+		l = w.unwrapLog()
+	}
+}
+
+func (m *MemoryLogger) unwrapLog() ILog {
+	return m.ILog
+}
+
+func (m *MemoryLogger) logFatal(text string, fields map[string]interface{}) {
+	fatal(m.ILog, text, fields)
+}
@@ -0,0 +1,75 @@
+package log
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"log"
+)
+
+// nopLog discards everything
+type nopLog struct{}
+
+// NewNopLogger creates a logger that discards every message, Fatal and Fatalf still panic
+func NewNopLogger() ILog {
+	return nopLog{}
+}
+
+func (nopLog) Warnf(string, ...interface{})         {}
+func (nopLog) Warn(...interface{})                  {}
+func (nopLog) Error(error, ...interface{})          {}
+func (nopLog) Errorf(error, string, ...interface{}) {}
+func (nopLog) Debug(...interface{})                 {}
+func (nopLog) Debugf(string, ...interface{})        {}
+func (nopLog) Print(...interface{})                 {}
+func (nopLog) Printf(string, ...interface{})        {}
+func (nopLog) GetWriter(Level) io.Writer            { return ioutil.Discard }
+func (nopLog) Close() error                         { return nil }
+func (nopLog) Pause()                               {}
+func (nopLog) Resume()                              {}
+func (nopLog) Flush()                               {}
+
+func (nopLog) Fatal(_ error, v ...interface{}) {
+	log.Panic(v...)
+}
+
+func (nopLog) Fatalf(_ error, format string, v ...interface{}) {
+	log.Panicf(format, v...)
+}
+
+func (n nopLog) WithField(string, interface{}) ILog {
+	return n
+}
+
+func (n nopLog) With(...Field) ILog {
+	return n
+}
+
+func (n nopLog) WithFields(map[string]interface{}) ILog {
+	return n
+}
+
+func (n nopLog) WithContext(context.Context) ILog {
+	return n
+}
+
+func (nopLog) SetLevel(Level) {}
+
+func (nopLog) enabled(Level) bool { return false }
+
+func (n nopLog) Named(string) ILog {
+	return n
+}
+
+func (n nopLog) Child(string, ...Field) ILog {
+	return n
+}
+
+func (nopLog) Trace(...interface{})               {}
+func (nopLog) Tracef(string, ...interface{})      {}
+func (nopLog) Log(Level, ...interface{})          {}
+func (nopLog) Logf(Level, string, ...interface{}) {}
+
+func (nopLog) Shutdown(context.Context) error {
+	return nil
+}
@@ -0,0 +1,190 @@
+package log
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Defaults for the limits on objects logged with InfoObj
+const (
+	DefaultMaxObjectDepth  = 5
+	DefaultMaxObjectFields = 100
+)
+
+// ObjectValueField holds the object logged with InfoObj when it is not a struct or map
+const ObjectValueField = "value"
+
+// truncatedValue replaces values past the depth limit
+const truncatedValue = "..."
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+type objectLimits struct {
+	depth  int
+	fields int
+}
+
+func newObjectLimits(settings Settings) objectLimits {
+	limits := objectLimits{depth: settings.MaxObjectDepth, fields: settings.MaxObjectFields}
+	if limits.depth <= 0 {
+		limits.depth = DefaultMaxObjectDepth
+	}
+	if limits.fields <= 0 {
+		limits.fields = DefaultMaxObjectFields
+	}
+	return limits
+}
+
+// objectLogger is implemented by the loggers in this package so InfoObj can use their settings
+type objectLogger interface {
+	infoObj(summary string, obj interface{})
+}
+
+// InfoObj logs the summary at info level with the exported fields of the object as the message fields.
+// Fields tagged with `log:"redact"` are masked, nesting past Settings.MaxObjectDepth is cut off and
+// only the first Settings.MaxObjectFields fields or elements are included at each level. Loggers from
+// other packages use the default limits.
+func InfoObj(l ILog, summary string, obj interface{}) {
+	if o, ok := unwrap(l).(objectLogger); ok {
+		o.infoObj(summary, obj)
+		return
+	}
+
+	l.WithFields(objectFields(obj, newObjectLimits(Settings{}))).Print(summary)
+}
+
+func (l logger) infoObj(summary string, obj interface{}) {
+	l.printFieldsLog(summary, INFO, objectFields(obj, newObjectLimits(l.settings)))
+}
+
+// objectFields gets the fields of a struct or map, any other value is put in the ObjectValueField field
+func objectFields(obj interface{}, limits objectLimits) map[string]interface{} {
+	if obj == nil {
+		return nil
+	}
+
+	value := objectValue(reflect.ValueOf(obj), 0, limits)
+	if fields, ok := value.(map[string]interface{}); ok {
+		return fields
+	}
+
+	return map[string]interface{}{ObjectValueField: value}
+}
+
+func keepAsIs(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) || t.Implements(errorType)
+}
+
+func objectValue(v reflect.Value, depth int, limits objectLimits) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	if v.CanInterface() && keepAsIs(v.Type()) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil
+		}
+		if err, ok := v.Interface().(error); ok {
+			return err.Error()
+		}
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return objectValue(v.Elem(), depth, limits)
+	case reflect.Struct:
+		if depth >= limits.depth {
+			return truncatedValue
+		}
+		result := map[string]interface{}{}
+		objectStruct(v, result, depth, limits)
+		return result
+	case reflect.Map:
+		if depth >= limits.depth {
+			return truncatedValue
+		}
+		if v.IsNil() {
+			return nil
+		}
+		result := map[string]interface{}{}
+		iter := v.MapRange()
+		for iter.Next() && len(result) < limits.fields {
+			result[fmt.Sprint(iter.Key().Interface())] = objectValue(iter.Value(), depth+1, limits)
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		if depth >= limits.depth {
+			return truncatedValue
+		}
+		count := v.Len()
+		if count > limits.fields {
+			count = limits.fields
+		}
+		result := make([]interface{}, count)
+		for i := range result {
+			result[i] = objectValue(v.Index(i), depth+1, limits)
+		}
+		return result
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return nil
+	}
+
+	if !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func objectStruct(v reflect.Value, result map[string]interface{}, depth int, limits objectLimits) {
+	t := v.Type()
+	for i := 0; i < t.NumField() && len(result) < limits.fields; i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		name, ok := jsonName(field)
+		if !ok {
+			continue
+		}
+
+		value := v.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			for value.Kind() == reflect.Ptr && !value.IsNil() {
+				value = value.Elem()
+			}
+
+			if value.Kind() == reflect.Struct {
+				objectStruct(value, result, depth, limits)
+				continue
+			}
+		}
+
+		if !value.CanInterface() {
+			continue
+		}
+
+		if isRedacted(field) {
+			result[name] = RedactedValue
+			continue
+		}
+
+		result[name] = objectValue(value, depth+1, limits)
+	}
+}
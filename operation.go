@@ -0,0 +1,59 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Fields added to the messages logged when an operation ends
+const (
+	OperationNameField     = "operation"
+	OperationDurationField = "duration_ms"
+	OperationErrorField    = "error"
+)
+
+// Operation is a timed unit of work started with StartOperation
+type Operation interface {
+	// End logs the operation and how long it took, it is logged at error level if err is not nil and at info level
+	// otherwise. Only the first call logs anything, the duration is returned either way.
+	End(err error) time.Duration
+}
+
+type operation struct {
+	log     ILog
+	clock   Clock
+	name    string
+	started time.Time
+	once    sync.Once
+}
+
+// StartOperation starts timing an operation, End logs it to the logger with its duration in OperationDurationField.
+// The operation is timed with Settings.Clock for the loggers in this package.
+func StartOperation(l ILog, name string) Operation {
+	var clock Clock = realClock{}
+	if c, ok := unwrap(l).(interface{ clock() Clock }); ok {
+		clock = c.clock()
+	}
+
+	return &operation{log: l, clock: clock, name: name, started: clock.Now()}
+}
+
+func (o *operation) End(err error) time.Duration {
+	dur := o.clock.Now().Sub(o.started)
+	o.once.Do(func() {
+		fields := map[string]interface{}{
+			OperationNameField:     o.name,
+			OperationDurationField: float64(dur) / float64(time.Millisecond),
+		}
+
+		if err != nil {
+			fields[OperationErrorField] = err.Error()
+			o.log.WithFields(fields).Log(ERROR, fmt.Sprintf("Operation %s failed after %s: %s", o.name, dur, err.Error()))
+			return
+		}
+
+		o.log.WithFields(fields).Log(INFO, fmt.Sprintf("Operation %s took %s", o.name, dur))
+	})
+	return dur
+}
@@ -0,0 +1,137 @@
+package log
+
+import "sync"
+
+// DefaultPauseBufferSize is the number of messages held while paused when Settings.PauseBufferSize is not set
+const DefaultPauseBufferSize = 1000
+
+// dropPaused is the drop reason for messages that did not fit in the buffer while publishing was paused
+const dropPaused = "paused"
+
+// pause holds messages while publishing is paused
+type pause struct {
+	mutex   sync.Mutex
+	changed *sync.Cond
+	paused  bool
+	size    int
+	policy  OverflowPolicy
+	held    []entry
+}
+
+func newPause(size int, policy OverflowPolicy) *pause {
+	if size <= 0 {
+		size = DefaultPauseBufferSize
+	}
+
+	p := &pause{size: size, policy: policy}
+	p.changed = sync.NewCond(&p.mutex)
+	return p
+}
+
+// hold the entry if publishing is paused, once the buffer is full the overflow policy decides
+// whether the oldest entry is dropped, the entry is dropped or the caller waits for Resume
+func (p *pause) hold(e entry) (held bool, dropped bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for p.paused && len(p.held) >= p.size {
+		switch p.policy {
+		case Block:
+			p.changed.Wait()
+			continue
+		case DropNewest:
+			return true, true
+		}
+
+		p.held = p.held[1:]
+		dropped = true
+	}
+
+	if !p.paused {
+		return false, dropped
+	}
+
+	p.held = append(p.held, e)
+	return true, dropped
+}
+
+// force holds the entry if publishing is paused even if the buffer is full
+func (p *pause) force(e entry) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.paused {
+		p.held = append(p.held, e)
+	}
+	return p.paused
+}
+
+// start pausing, the entries are older than any held so far so they are held first
+func (p *pause) start(entries []entry) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.paused = true
+	if len(entries) != 0 {
+		p.held = append(entries, p.held...)
+	}
+}
+
+// take the held entries, when there are no more entries publishing is resumed
+func (p *pause) take() []entry {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	held := p.held
+	p.held = nil
+
+	if len(held) == 0 {
+		p.paused = false
+	}
+
+	p.changed.Broadcast()
+	return held
+}
+
+// hold the entry if publishing is paused
+func (l logger) hold(e entry) bool {
+	held, dropped := l.pause.hold(e)
+	if dropped {
+		l.dropped(dropPaused)
+	}
+	return held
+}
+
+// Pause stops sending messages to the publishers, the messages are held until Resume is called and once
+// Settings.PauseBufferSize are held Settings.OverflowPolicy applies. Messages waiting in the async buffer stay
+// there and those collected for a batch are held, publishes already in progress finish before Pause returns.
+// Console output continues while paused.
+func (l logger) Pause() {
+	var batched []entry
+	if l.batcher != nil {
+		batched = l.batcher.take(false)
+	}
+	l.pause.start(batched)
+
+	if l.queue != nil {
+		l.queue.pause()
+	}
+}
+
+// Resume sends the messages held while paused to the publishers in order and resumes publishing
+func (l logger) Resume() {
+	if l.queue != nil {
+		l.queue.resume()
+	}
+
+	for {
+		held := l.pause.take()
+		if len(held) == 0 {
+			return
+		}
+
+		for _, e := range held {
+			l.deliver(e)
+		}
+	}
+}
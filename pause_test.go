@@ -0,0 +1,149 @@
+package log
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordPublisher records the text of the messages it publishes
+type recordPublisher struct {
+	mutex sync.Mutex
+	texts []string
+}
+
+func (p *recordPublisher) Publish(messageBites []byte) error {
+	var message Message
+	if err := json.Unmarshal(messageBites, &message); err != nil {
+		return err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.texts = append(p.texts, message.Text)
+	return nil
+}
+
+func (p *recordPublisher) published() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]string(nil), p.texts...)
+}
+
+func newRecordLogger(settings Settings) (ILog, *recordPublisher) {
+	publisher := &recordPublisher{}
+	settings.MinLogLevel = INFO
+	settings.CustomPublishers = []CustomPublisher{{Name: "record", Publisher: publisher}}
+	return Create(settings), publisher
+}
+
+func TestPauseOverflowPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   OverflowPolicy
+		expected []string
+	}{
+		{name: "drop oldest", policy: DropOldest, expected: []string{"3", "4"}},
+		{name: "drop newest", policy: DropNewest, expected: []string{"1", "2"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l, publisher := newRecordLogger(Settings{PauseBufferSize: 2, OverflowPolicy: test.policy})
+			l.Pause()
+			for _, text := range []string{"1", "2", "3", "4"} {
+				l.Print(text)
+			}
+
+			if published := publisher.published(); len(published) != 0 {
+				t.Fatalf("published %v while paused", published)
+			}
+
+			l.Resume()
+			if published := publisher.published(); !reflect.DeepEqual(published, test.expected) {
+				t.Errorf("published %v, expected %v", published, test.expected)
+			}
+		})
+	}
+}
+
+func TestPauseBlock(t *testing.T) {
+	l, publisher := newRecordLogger(Settings{PauseBufferSize: 1, OverflowPolicy: Block})
+	l.Pause()
+	l.Print("1")
+
+	logged := make(chan struct{})
+	go func() {
+		defer close(logged)
+		l.Print("2")
+	}()
+
+	select {
+	case <-logged:
+		t.Fatal("logging did not wait for room in the pause buffer")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	l.Resume()
+	<-logged
+	l.Flush()
+
+	if published, expected := publisher.published(), []string{"1", "2"}; !reflect.DeepEqual(published, expected) {
+		t.Errorf("published %v, expected %v", published, expected)
+	}
+}
+
+func TestPauseStopsBufferedMessages(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+	}{
+		{name: "async", settings: Settings{Async: true}},
+		{name: "batch", settings: Settings{BatchSize: 10}},
+		{name: "async batch", settings: Settings{Async: true, BatchSize: 10}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l, publisher := newRecordLogger(test.settings)
+			for _, text := range []string{"1", "2", "3"} {
+				l.Print(text)
+			}
+
+			l.Pause()
+			published := publisher.published()
+			l.Print("4")
+			l.Flush()
+			time.Sleep(10 * time.Millisecond)
+			if after := publisher.published(); len(after) != len(published) {
+				t.Fatalf("published %v after pausing", after[len(published):])
+			}
+
+			l.Resume()
+			if err := l.Close(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if published, expected := publisher.published(), []string{"1", "2", "3", "4"}; !reflect.DeepEqual(published, expected) {
+				t.Errorf("published %v, expected %v", published, expected)
+			}
+		})
+	}
+}
+
+func TestFatalPublishesHeldMessages(t *testing.T) {
+	l, publisher := newRecordLogger(Settings{})
+	l.Pause()
+	l.Print("held")
+
+	func() {
+		defer func() { _ = recover() }()
+		l.Fatal(nil, "fatal")
+	}()
+
+	if published, expected := publisher.published(), []string{"held", "fatal"}; !reflect.DeepEqual(published, expected) {
+		t.Errorf("published %v, expected %v", published, expected)
+	}
+}
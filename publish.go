@@ -0,0 +1,259 @@
+package log
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cjburchell/uatu-go/publishers"
+)
+
+// entry is an encoded message waiting to be published
+type entry struct {
+	message Message
+	data    []byte
+	printed bool
+}
+
+// sink is a publisher along with the logger's state for it
+type sink struct {
+	// the counters are first so they are aligned for atomic access
+	stalls    uint64
+	published uint64
+	failed    uint64
+	// failing is the number of publishes in a row that failed
+	failing uint64
+
+	name           string
+	publisher      publishers.Publisher
+	minLevel       Level
+	spool          *spool
+	mutex          sync.Mutex
+	abandonedUntil time.Time
+}
+
+// newSink creates a sink that publishes messages at or above the level
+func newSink(name string, publisher publishers.Publisher, minLevel Level) *sink {
+	return &sink{name: name, publisher: publisher, minLevel: minLevel}
+}
+
+// accepts is true if messages at the level are sent to the publisher, a publisher without a level gets every message
+func (s *sink) accepts(level Level) bool {
+	return s.minLevel == Level{} || level.Severity >= s.minLevel.Severity
+}
+
+// enabled is false for gated publishers that are turned off
+func (s *sink) enabled() bool {
+	if gated, ok := s.publisher.(publishers.Gated); ok {
+		return gated.Enabled()
+	}
+	return true
+}
+
+// abandoned is true while the publisher is being skipped after it was blocked
+func (s *sink) abandoned() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return time.Now().Before(s.abandonedUntil)
+}
+
+func (s *sink) abandon(duration time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.abandonedUntil = time.Now().Add(duration)
+}
+
+var errAbandoned = errors.New("publisher abandoned after blocking")
+
+// send the entry to the publishers unless publishing is paused
+func (l logger) send(e entry) {
+	if l.hold(e) {
+		return
+	}
+
+	l.deliver(e)
+}
+
+func (l logger) publish(e entry) {
+	delivered, skipped := false, 0
+	for _, s := range l.sinks {
+		if !s.accepts(e.message.Level) || !s.enabled() {
+			skipped++
+			continue
+		}
+
+		// spooled messages are published first, while they can not be the message is spooled behind them
+		if !l.replaySpool(s) {
+			delivered = l.spoolFailed(s, e.data) || delivered
+			continue
+		}
+
+		err := l.watch(s, func() error { return s.publisher.Publish(e.data) })
+		if err == errAbandoned {
+			continue
+		}
+
+		if err != nil {
+			l.publishFailed(s, 1)
+			l.publishError(s, err, e.message)
+			delivered = l.spoolFailed(s, e.data) || delivered
+		} else {
+			l.publishSucceeded(s, 1)
+			delivered = true
+		}
+	}
+
+	if !delivered && (skipped == 0 || skipped < len(l.sinks)) && l.publishersDead() {
+		l.fallback.write(e.message, e.printed)
+	}
+}
+
+func (l logger) publishBatch(batch []entry) {
+	if len(batch) == 0 {
+		return
+	}
+
+	delivered, skipped := make([]bool, len(batch)), make([]int, len(batch))
+	for _, s := range l.sinks {
+		if !s.enabled() {
+			for i := range skipped {
+				skipped[i]++
+			}
+			continue
+		}
+
+		// only the messages at or above the publisher's level are sent to it
+		indexes, data := make([]int, 0, len(batch)), make([][]byte, 0, len(batch))
+		for i, e := range batch {
+			if s.accepts(e.message.Level) {
+				indexes = append(indexes, i)
+				data = append(data, e.data)
+			} else {
+				skipped[i]++
+			}
+		}
+
+		if len(data) == 0 {
+			continue
+		}
+
+		if !l.replaySpool(s) {
+			for _, i := range indexes {
+				delivered[i] = l.spoolFailed(s, batch[i].data) || delivered[i]
+			}
+			continue
+		}
+
+		if batchPublisher, ok := s.publisher.(publishers.BatchPublisher); ok {
+			err := l.watch(s, func() error { return batchPublisher.PublishBatch(data) })
+			if err == errAbandoned {
+				continue
+			}
+
+			if err != nil {
+				if l.settings.OnPublishError == nil {
+					l.internal().Printf("Unable to send batch of %d logs to publisher %s (%s)", len(data), s.name, err.Error())
+				}
+				l.publishFailed(s, len(indexes))
+				for _, i := range indexes {
+					l.publishError(s, err, batch[i].message)
+					delivered[i] = l.spoolFailed(s, batch[i].data) || delivered[i]
+				}
+			} else {
+				l.publishSucceeded(s, len(indexes))
+				for _, i := range indexes {
+					delivered[i] = true
+				}
+			}
+			continue
+		}
+
+		for i, messageBites := range data {
+			// once a message is spooled the rest are spooled behind it unless the spool can be replayed
+			if i != 0 && !l.replaySpool(s) {
+				delivered[indexes[i]] = l.spoolFailed(s, messageBites) || delivered[indexes[i]]
+				continue
+			}
+
+			err := l.watch(s, func() error { return s.publisher.Publish(messageBites) })
+			if err == errAbandoned {
+				break
+			}
+
+			if err != nil {
+				l.publishFailed(s, 1)
+				l.publishError(s, err, batch[indexes[i]].message)
+				delivered[indexes[i]] = l.spoolFailed(s, messageBites) || delivered[indexes[i]]
+			} else {
+				l.publishSucceeded(s, 1)
+				delivered[indexes[i]] = true
+			}
+		}
+	}
+
+	for i, e := range batch {
+		if !delivered[i] && (skipped[i] == 0 || skipped[i] < len(l.sinks)) && l.publishersDead() {
+			l.fallback.write(e.message, e.printed)
+		}
+	}
+}
+
+// publishError reports a failed publish to Settings.OnPublishError or the internal logger if it is not set
+func (l logger) publishError(s *sink, err error, message Message) {
+	if l.settings.OnPublishError != nil {
+		l.settings.OnPublishError(err, message)
+		return
+	}
+
+	l.internal().Printf("Unable to send log to publisher %s (%s): %s", s.name, err.Error(), message.String())
+}
+
+// watch publishes with retries and warns if the publish takes longer than Settings.PublishTimeout.
+// The check is made by a timer for each publish so the warning is logged once the timeout passes even
+// if the publish never returns, the publish itself is not interrupted. When Settings.BlockedPublisherCooldown
+// is set the publisher is then skipped for that long.
+func (l logger) watch(s *sink, publish func() error) error {
+	if s.abandoned() {
+		return errAbandoned
+	}
+
+	timeout := l.settings.PublishTimeout
+	if timeout <= 0 {
+		return l.retry(s, publish)
+	}
+
+	timer := time.AfterFunc(timeout, func() {
+		atomic.AddUint64(&s.stalls, 1)
+		l.internal().Printf("Publisher %s has been blocked publishing a log for more than %s", s.name, timeout)
+		if l.settings.BlockedPublisherCooldown > 0 {
+			s.abandon(l.settings.BlockedPublisherCooldown)
+		}
+	})
+	defer timer.Stop()
+
+	return l.retry(s, publish)
+}
+
+// retry the publish up to Settings.PublishRetries times while the shared retry budget allows it,
+// waiting Settings.RetryBackoff before the first retry and doubling the wait up to Settings.RetryMaxBackoff.
+// Publishers that retry on their own are not retried again.
+func (l logger) retry(s *sink, publish func() error) error {
+	err := publish()
+	if retrier, ok := s.publisher.(publishers.Retrier); ok && retrier.Retries() {
+		return err
+	}
+
+	backoff := l.settings.RetryBackoff
+	for attempt := 0; err != nil && attempt < l.settings.PublishRetries && l.budget.Allow(); attempt++ {
+		if backoff > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+			if l.settings.RetryMaxBackoff > 0 && backoff > l.settings.RetryMaxBackoff {
+				backoff = l.settings.RetryMaxBackoff
+			}
+		}
+		err = publish()
+	}
+	return err
+}
@@ -0,0 +1,14 @@
+package log
+
+import "github.com/cjburchell/uatu-go/publishers"
+
+// Publisher sends encoded messages to a log sink, it is the same as publishers.Publisher
+type Publisher = publishers.Publisher
+
+// CustomPublisher is a publisher provided by the user along with the name used when reporting its errors
+type CustomPublisher struct {
+	Name      string
+	Publisher Publisher
+	// MinLogLevel is the lowest level sent to the publisher, messages below the logger's level are not sent either
+	MinLogLevel Level
+}
@@ -0,0 +1,50 @@
+package publishers
+
+import (
+	"sync"
+	"time"
+)
+
+// RetryBudget is a token bucket shared between publishers that bounds the total number of retries they make,
+// so a long outage does not have every publisher retrying every message
+type RetryBudget struct {
+	mutex  sync.Mutex
+	tokens float64
+	size   float64
+	refill float64
+	last   time.Time
+}
+
+// NewRetryBudget creates a budget allowing size retries at once that refills at refillPerSecond retries a second
+func NewRetryBudget(size int, refillPerSecond float64) *RetryBudget {
+	return &RetryBudget{
+		tokens: float64(size),
+		size:   float64(size),
+		refill: refillPerSecond,
+		last:   time.Now(),
+	}
+}
+
+// Allow takes a retry from the budget returning false if the budget is used up, a nil budget always allows the retry
+func (b *RetryBudget) Allow() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.refill
+	if b.tokens > b.size {
+		b.tokens = b.size
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
@@ -0,0 +1,11 @@
+package publishers
+
+// EventLogSettings struct
+type EventLogSettings struct {
+	// Source the events are logged under
+	Source string
+	// EventID of the logged events
+	EventID uint32
+	// Install registers the source with the event log if it is not registered yet, this needs administrator rights
+	Install bool
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+// +build !windows
+
+package publishers
+
+import "fmt"
+
+// SetupEventLog sets up the windows event log publisher, on other platforms it returns an error
+func SetupEventLog(newSettings EventLogSettings) (Publisher, error) {
+	return nil, fmt.Errorf("unable to log to the %s event log, the event log is only supported on windows", newSettings.Source)
+}
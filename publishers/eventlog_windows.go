@@ -0,0 +1,72 @@
+//go:build windows
+// +build windows
+
+package publishers
+
+import (
+	"encoding/json"
+
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// eventLogMessage is the part of the message the event log publisher needs
+type eventLogMessage struct {
+	Text  string `json:"text"`
+	Level struct {
+		Severity int `json:"Severity"`
+	} `json:"level"`
+}
+
+// severities of the log levels used to pick the event type
+const (
+	warningSeverity = 2
+	errorSeverity   = 3
+)
+
+func decodeEventLogMessage(messageBites []byte) (eventLogMessage, error) {
+	var message eventLogMessage
+	err := json.Unmarshal(messageBites, &message)
+	return message, err
+}
+
+type eventLogPublisher struct {
+	log      *eventlog.Log
+	settings EventLogSettings
+}
+
+// Publish message
+func (publisher eventLogPublisher) Publish(messageBites []byte) error {
+	message, err := decodeEventLogMessage(messageBites)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case message.Level.Severity >= errorSeverity:
+		return publisher.log.Error(publisher.settings.EventID, message.Text)
+	case message.Level.Severity >= warningSeverity:
+		return publisher.log.Warning(publisher.settings.EventID, message.Text)
+	default:
+		return publisher.log.Info(publisher.settings.EventID, message.Text)
+	}
+}
+
+// Close the event log
+func (publisher eventLogPublisher) Close() error {
+	return publisher.log.Close()
+}
+
+// SetupEventLog sets up the windows event log publisher
+func SetupEventLog(newSettings EventLogSettings) (Publisher, error) {
+	if newSettings.Install {
+		// the source may already be registered in which case the error is ignored and opening it below decides
+		_ = eventlog.InstallAsEventCreate(newSettings.Source, eventlog.Error|eventlog.Warning|eventlog.Info)
+	}
+
+	log, err := eventlog.Open(newSettings.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	return eventLogPublisher{log: log, settings: newSettings}, nil
+}
@@ -0,0 +1,308 @@
+package publishers
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is added to the name of rotated files
+const backupTimeFormat = "20060102T150405.000"
+
+// backupTimePattern matches backupTimeFormat and the number added when a backup with the same time exists
+const backupTimePattern = `(\d{8}T\d{6}\.\d{3})(?:\.(\d+))?`
+
+// FileSettings struct
+type FileSettings struct {
+	// Path of the log file, rotated files are kept next to it
+	Path string
+	// MaxSize in bytes the file can grow to before it is rotated, 0 does not rotate on size
+	MaxSize int64
+	// MaxAge the file is written to before it is rotated, 0 does not rotate on age
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files kept, 0 keeps them all
+	MaxBackups int
+	// Compress gzips the rotated files
+	Compress bool
+	// Now gets the time MaxAge and the names of rotated files use, defaults to time.Now
+	Now func() time.Time
+}
+
+type filePublisher struct {
+	mutex    sync.Mutex
+	settings FileSettings
+	file     *os.File
+	size     int64
+	opened   time.Time
+	closed   bool
+
+	// rotated backups are compressed and pruned one at a time by a single goroutine
+	cleanMutex sync.Mutex
+	pending    []string
+	cleaning   bool
+	cleaned    sync.WaitGroup
+}
+
+// SetupFile sets up a publisher that writes each message as a line in a file
+func SetupFile(settings FileSettings) (Publisher, error) {
+	if settings.Path == "" {
+		return nil, fmt.Errorf("no path given for the log file")
+	}
+
+	publisher := &filePublisher{settings: settings}
+	if err := publisher.open(); err != nil {
+		return nil, err
+	}
+
+	return publisher, nil
+}
+
+func (publisher *filePublisher) open() error {
+	if err := os.MkdirAll(filepath.Dir(publisher.settings.Path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(publisher.settings.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	publisher.file = file
+	publisher.size = info.Size()
+	publisher.opened = publisher.now()
+	return nil
+}
+
+func (publisher *filePublisher) Publish(messageBites []byte) error {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+
+	if publisher.closed {
+		return fmt.Errorf("log file %s is closed", publisher.settings.Path)
+	}
+
+	// the file is not open if it could not be opened again after an earlier rotation
+	if publisher.file == nil {
+		if err := publisher.open(); err != nil {
+			return err
+		}
+	}
+
+	line := append(append(make([]byte, 0, len(messageBites)+1), messageBites...), '\n')
+	if publisher.rotateDue(int64(len(line))) {
+		if err := publisher.rotate(); err != nil {
+			return err
+		}
+	}
+
+	written, err := publisher.file.Write(line)
+	publisher.size += int64(written)
+	return err
+}
+
+func (publisher *filePublisher) rotateDue(size int64) bool {
+	if publisher.size == 0 {
+		return false
+	}
+
+	if publisher.settings.MaxSize > 0 && publisher.size+size > publisher.settings.MaxSize {
+		return true
+	}
+
+	return publisher.settings.MaxAge > 0 && publisher.now().Sub(publisher.opened) >= publisher.settings.MaxAge
+}
+
+// rotate moves the current file to a backup and opens a new one. If the file can not be moved the current
+// file is opened again so writing continues and the rotation is tried again later.
+func (publisher *filePublisher) rotate() error {
+	closeErr := publisher.file.Close()
+	publisher.file = nil
+
+	backup := publisher.backupName(publisher.now())
+	if closeErr != nil || os.Rename(publisher.settings.Path, backup) != nil {
+		return publisher.open()
+	}
+
+	if err := publisher.open(); err != nil {
+		return err
+	}
+
+	publisher.cleanup(backup)
+	return nil
+}
+
+// cleanup compresses the backup and prunes the old backups in the background, backups rotated while
+// an earlier one is being cleaned up are done by the same goroutine afterwards
+func (publisher *filePublisher) cleanup(backup string) {
+	publisher.cleanMutex.Lock()
+	defer publisher.cleanMutex.Unlock()
+
+	publisher.pending = append(publisher.pending, backup)
+	if publisher.cleaning {
+		return
+	}
+
+	publisher.cleaning = true
+	publisher.cleaned.Add(1)
+	go func() {
+		defer publisher.cleaned.Done()
+		for {
+			publisher.cleanMutex.Lock()
+			if len(publisher.pending) == 0 {
+				publisher.cleaning = false
+				publisher.cleanMutex.Unlock()
+				return
+			}
+			backup := publisher.pending[0]
+			publisher.pending = publisher.pending[1:]
+			publisher.cleanMutex.Unlock()
+
+			if publisher.settings.Compress {
+				_ = compressFile(backup)
+			}
+			publisher.prune()
+		}
+	}()
+}
+
+func (publisher *filePublisher) now() time.Time {
+	if publisher.settings.Now == nil {
+		return time.Now()
+	}
+	return publisher.settings.Now()
+}
+
+func (publisher *filePublisher) backupName(now time.Time) string {
+	ext := filepath.Ext(publisher.settings.Path)
+	base := strings.TrimSuffix(publisher.settings.Path, ext)
+	name := fmt.Sprintf("%s-%s%s", base, now.Format(backupTimeFormat), ext)
+	for i := 1; fileExists(name) || fileExists(name+".gz"); i++ {
+		name = fmt.Sprintf("%s-%s.%d%s", base, now.Format(backupTimeFormat), i, ext)
+	}
+	return name
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// prune removes the oldest backups past MaxBackups
+func (publisher *filePublisher) prune() {
+	if publisher.settings.MaxBackups <= 0 {
+		return
+	}
+
+	backups, err := findBackups(publisher.settings.Path)
+	if err != nil {
+		return
+	}
+
+	for len(backups) > publisher.settings.MaxBackups {
+		_ = os.Remove(backups[0].path)
+		backups = backups[1:]
+	}
+}
+
+type backupFile struct {
+	path  string
+	time  string
+	index int
+}
+
+// findBackups gets the backups of the log file oldest first, only the files named by backupName are included
+func findBackups(path string) ([]backupFile, error) {
+	dir := filepath.Dir(path)
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(filepath.Base(path), ext)
+	pattern := regexp.MustCompile("^" + regexp.QuoteMeta(base) + "-" + backupTimePattern + regexp.QuoteMeta(ext) + `(?:\.gz)?$`)
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backupFile
+	for _, file := range files {
+		match := pattern.FindStringSubmatch(file.Name())
+		if match == nil || file.IsDir() {
+			continue
+		}
+
+		backup := backupFile{path: filepath.Join(dir, file.Name()), time: match[1]}
+		if match[2] != "" {
+			backup.index, _ = strconv.Atoi(match[2])
+		}
+		backups = append(backups, backup)
+	}
+
+	// the time in the name sorts the backups oldest first
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].time != backups[j].time {
+			return backups[i].time < backups[j].time
+		}
+		return backups[i].index < backups[j].index
+	})
+	return backups, nil
+}
+
+func compressFile(path string) error {
+	source, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+
+	writer := gzip.NewWriter(target)
+	if _, err = io.Copy(writer, source); err == nil {
+		err = writer.Close()
+	}
+
+	if closeErr := target.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// Close the file, it is safe to call more than once
+func (publisher *filePublisher) Close() error {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+
+	if publisher.closed {
+		return nil
+	}
+
+	publisher.closed = true
+	publisher.cleaned.Wait()
+	if publisher.file == nil {
+		return nil
+	}
+	return publisher.file.Close()
+}
@@ -0,0 +1,168 @@
+package publishers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestPruneOnlyRemovesBackups(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		files      []string
+		maxBackups int
+		expected   []string
+	}{
+		{
+			name:       "keeps unrelated files",
+			path:       "app.log",
+			files:      []string{"app.log", "app-server.log", "app-audit.log.gz", "app-20200101T000000.000.log", "app-20200102T000000.000.log.gz"},
+			maxBackups: 1,
+			expected:   []string{"app-20200102T000000.000.log.gz", "app-audit.log.gz", "app-server.log", "app.log"},
+		},
+		{
+			name:       "numbered backups sort after the first with the same time",
+			path:       "app.log",
+			files:      []string{"app-20200101T000000.000.2.log", "app-20200101T000000.000.log", "app-20200101T000000.000.10.log"},
+			maxBackups: 2,
+			expected:   []string{"app-20200101T000000.000.10.log", "app-20200101T000000.000.2.log"},
+		},
+		{
+			name:       "no extension",
+			path:       "app",
+			files:      []string{"app", "app-old", "app-20200101T000000.000", "app-20200102T000000.000.gz"},
+			maxBackups: 1,
+			expected:   []string{"app", "app-20200102T000000.000.gz", "app-old"},
+		},
+		{
+			name:       "other extension",
+			path:       "app.log",
+			files:      []string{"app-20200101T000000.000.txt", "app-20200101T000000.000.log", "app-20200102T000000.000.log"},
+			maxBackups: 1,
+			expected:   []string{"app-20200101T000000.000.txt", "app-20200102T000000.000.log"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "file-publisher")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			for _, name := range test.files {
+				if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			publisher := &filePublisher{settings: FileSettings{Path: filepath.Join(dir, test.path), MaxBackups: test.maxBackups}}
+			publisher.prune()
+
+			files, err := ioutil.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			names := make([]string, 0, len(files))
+			for _, file := range files {
+				names = append(names, file.Name())
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, test.expected) {
+				t.Errorf("files left are %v, expected %v", names, test.expected)
+			}
+		})
+	}
+}
+
+func TestRotateCompressesAndPrunes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file-publisher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	publisher, err := SetupFile(FileSettings{Path: path, MaxSize: 10, MaxBackups: 2, Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 6; i++ {
+		if err := publisher.Publish([]byte("a message")); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if err := publisher.(*filePublisher).Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	backups, err := findBackups(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(backups) != 2 {
+		t.Fatalf("%d backups kept, expected 2", len(backups))
+	}
+
+	for _, backup := range backups {
+		if filepath.Ext(backup.path) != ".gz" {
+			t.Errorf("backup %s was not compressed", backup.path)
+		}
+	}
+}
+
+func TestRotateOnMaxAge(t *testing.T) {
+	tests := []struct {
+		name     string
+		elapsed  time.Duration
+		expected int
+	}{
+		{name: "before max age", elapsed: 59 * time.Minute, expected: 0},
+		{name: "at max age", elapsed: time.Hour, expected: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "file-publisher")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			path := filepath.Join(dir, "app.log")
+			publisher, err := SetupFile(FileSettings{Path: path, MaxAge: time.Hour, Now: func() time.Time { return now }})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_ = publisher.Publish([]byte("first"))
+			now = now.Add(test.elapsed)
+			_ = publisher.Publish([]byte("second"))
+			if err := publisher.(*filePublisher).Close(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			backups, err := findBackups(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(backups) != test.expected {
+				t.Errorf("%d backups, expected %d", len(backups), test.expected)
+			}
+			if test.expected != 0 && filepath.Base(backups[0].path) != "app-20200101T010000.000.log" {
+				t.Errorf("backup is named %s", filepath.Base(backups[0].path))
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package publishers
+
+import "io"
+
+// Gated is implemented by publishers that can be turned on and off while running
+type Gated interface {
+	// Enabled is true if the publisher should be sent messages
+	Enabled() bool
+}
+
+type gatedPublisher struct {
+	publisher Publisher
+	enabled   func() bool
+}
+
+// gatedBatchPublisher is a gated publisher that can send several messages at once
+type gatedBatchPublisher struct {
+	gatedPublisher
+}
+
+// Gate only sends messages to the publisher while enabled returns true, e.g. to roll out a new publisher behind
+// a feature flag. enabled is called for every message so it must be fast. The gated publisher is only a
+// BatchPublisher if the publisher is one.
+func Gate(publisher Publisher, enabled func() bool) Publisher {
+	gated := gatedPublisher{publisher: publisher, enabled: enabled}
+	if _, ok := publisher.(BatchPublisher); ok {
+		return gatedBatchPublisher{gated}
+	}
+	return gated
+}
+
+// Enabled is true if the publisher should be sent messages
+func (publisher gatedPublisher) Enabled() bool {
+	return publisher.enabled()
+}
+
+// Publish message if the publisher is enabled
+func (publisher gatedPublisher) Publish(messageBites []byte) error {
+	if !publisher.enabled() {
+		return nil
+	}
+
+	return publisher.publisher.Publish(messageBites)
+}
+
+// PublishBatch messages if the publisher is enabled
+func (publisher gatedBatchPublisher) PublishBatch(messages [][]byte) error {
+	if !publisher.enabled() {
+		return nil
+	}
+
+	return publisher.publisher.(BatchPublisher).PublishBatch(messages)
+}
+
+// Retries is true if the wrapped publisher retries failed publishes itself
+func (publisher gatedPublisher) Retries() bool {
+	retrier, ok := publisher.publisher.(Retrier)
+	return ok && retrier.Retries()
+}
+
+// Close the publisher if it needs to be closed
+func (publisher gatedPublisher) Close() error {
+	if closer, ok := publisher.publisher.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
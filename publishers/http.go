@@ -2,14 +2,56 @@ package publishers
 
 import (
 	"bytes"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
 	"net/http"
+	"time"
+)
+
+// Defaults used when the HTTPSettings are not set
+const (
+	DefaultHTTPTimeout   = 10 * time.Second
+	DefaultHTTPBaseDelay = 100 * time.Millisecond
+	DefaultHTTPMaxDelay  = 5 * time.Second
 )
 
 // HTTPSettings struct
 type HTTPSettings struct {
 	Address string
 	Token   string
+	// RequestInterceptor is called with each request before it is sent so it can add headers, change the url
+	// or sign the body. Returning an error stops the request from being sent and the publish fails without retrying.
+	RequestInterceptor func(request *http.Request) error
+	// MaxAttempts is the number of times a message is sent before giving up, any 2xx response is a success.
+	// Connection errors and 5xx responses are retried while other responses fail right away. Defaults to 1.
+	MaxAttempts int
+	// RetryBudget is shared with the other publishers to bound the retries they make, each retry takes a token
+	// and the publish fails once the budget is used up. The logger sets it to its own budget if it is nil.
+	RetryBudget *RetryBudget
+	// BaseDelay is the delay before the first retry, it doubles on each retry up to MaxDelay with jitter added
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Timeout is the limit for each request, defaults to DefaultHTTPTimeout
+	Timeout time.Duration
+	// BatchAddress is the endpoint batches of messages are posted to as a JSON array,
+	// when it is empty messages in a batch are posted to Address one at a time
+	BatchAddress string
+	// BearerToken is sent as a bearer token in place of the Token
+	BearerToken string
+	// Username and Password are sent with basic auth in place of the Token
+	Username string
+	Password string
+	// Headers are added to every request
+	Headers map[string]string
+	// CertFile and KeyFile are the client certificate sent to the server
+	CertFile string
+	KeyFile  string
+	// CAFile is a bundle of certificates used to verify the server in place of the system ones
+	CAFile string
 }
 
 type httpPublisher struct {
@@ -17,29 +59,230 @@ type httpPublisher struct {
 	settings   HTTPSettings
 }
 
+// statusError is an unexpected response from the server
+type statusError struct {
+	address string
+	code    int
+}
+
+func (err statusError) Error() string {
+	return fmt.Sprintf("unable to send log to %s(%d)", err.address, err.code)
+}
+
+// temporary is true for the server errors that are worth retrying
+func (err statusError) temporary() bool {
+	return err.code >= 500
+}
+
+// interceptorError is returned when the RequestInterceptor rejects a request, it is never retried
+type interceptorError struct {
+	err error
+}
+
+func (err interceptorError) Error() string {
+	return fmt.Sprintf("request interceptor failed: %s", err.err.Error())
+}
+
+// Unwrap is the error returned by the RequestInterceptor
+func (err interceptorError) Unwrap() error {
+	return err.err
+}
+
+type httpBatchPublisher struct {
+	httpPublisher
+}
+
+// PublishBatch posts the messages to the BatchAddress as a JSON array
+func (publisher httpBatchPublisher) PublishBatch(messages [][]byte) error {
+	return publisher.retry(func() error {
+		return publisher.post(publisher.settings.BatchAddress, batchArray(messages))
+	})
+}
+
+// batchArray joins the encoded messages into a JSON array
+func batchArray(messages [][]byte) []byte {
+	return append(append([]byte{'['}, bytes.Join(messages, []byte{','})...), ']')
+}
+
 func (publisher httpPublisher) Publish(messageBites []byte) error {
-	req, err := http.NewRequest("POST", publisher.settings.Address, bytes.NewBuffer(messageBites))
+	return publisher.retry(func() error {
+		return publisher.post(publisher.settings.Address, messageBites)
+	})
+}
+
+// Close the idle connections to the server
+func (publisher httpPublisher) Close() error {
+	publisher.restClient.CloseIdleConnections()
+	return nil
+}
+
+// Retries is true when MaxAttempts allows retries so the logger does not retry the publish again
+func (publisher httpPublisher) Retries() bool {
+	return publisher.settings.MaxAttempts > 1
+}
+
+// retry the send up to MaxAttempts times while the RetryBudget allows it
+func (publisher httpPublisher) retry(send func() error) error {
+	attempts := publisher.settings.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt != 0 {
+			if !publisher.settings.RetryBudget.Allow() {
+				return err
+			}
+			time.Sleep(publisher.delay(attempt))
+		}
+
+		err = send()
+		if err == nil {
+			return nil
+		}
+
+		if status, ok := err.(statusError); ok && !status.temporary() {
+			return err
+		}
+
+		if _, ok := err.(interceptorError); ok {
+			return err
+		}
+	}
+
+	return err
+}
+
+// delay is the exponential backoff with full jitter before the retry
+func (publisher httpPublisher) delay(attempt int) time.Duration {
+	base, limit := publisher.settings.BaseDelay, publisher.settings.MaxDelay
+	if base <= 0 {
+		base = DefaultHTTPBaseDelay
+	}
+	if limit <= 0 {
+		limit = DefaultHTTPMaxDelay
+	}
+
+	delay := base
+	for i := 1; i < attempt && delay < limit; i++ {
+		delay *= 2
+	}
+	if delay > limit {
+		delay = limit
+	}
+
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
+
+func (publisher httpPublisher) post(address string, body []byte) error {
+	req, err := http.NewRequest("POST", address, bytes.NewBuffer(body))
 	if err != nil {
 		return err
 	}
 
-	req.Header.Add("Authorization", fmt.Sprintf("APIKEY %s", publisher.settings.Token))
+	switch {
+	case publisher.settings.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+publisher.settings.BearerToken)
+	case publisher.settings.Username != "":
+		req.SetBasicAuth(publisher.settings.Username, publisher.settings.Password)
+	default:
+		req.Header.Add("Authorization", fmt.Sprintf("APIKEY %s", publisher.settings.Token))
+	}
 	req.Header.Add("Content-Type", "application/json")
+	for key, value := range publisher.settings.Headers {
+		req.Header.Set(key, value)
+	}
+
+	if publisher.settings.RequestInterceptor != nil {
+		err = publisher.settings.RequestInterceptor(req)
+		if err != nil {
+			return interceptorError{err: err}
+		}
+	}
 
 	resp, err := publisher.restClient.Do(req)
 	if err != nil {
 		return err
 	}
 
-	if resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("unable to send log to %s(%d)", publisher.settings.Address, resp.StatusCode)
+	// drain the body so the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return statusError{address: address, code: resp.StatusCode}
 	}
 
 	return nil
 }
 
-// SetupHTTP sets up the http client
+// SetupHTTP sets up the http client, if the TLS files can not be loaded every publish returns the error
 func SetupHTTP(newSettings HTTPSettings) Publisher {
-	restClient := &http.Client{}
-	return httpPublisher{restClient: restClient, settings: newSettings}
+	publisher, err := NewHTTP(newSettings)
+	if err != nil {
+		return failedPublisher{err: err}
+	}
+	return publisher
+}
+
+// NewHTTP sets up the http client returning an error if the TLS files can not be loaded
+func NewHTTP(newSettings HTTPSettings) (Publisher, error) {
+	timeout := newSettings.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHTTPTimeout
+	}
+
+	restClient := &http.Client{Timeout: timeout}
+	if newSettings.CertFile != "" || newSettings.CAFile != "" {
+		tlsConfig, err := loadTLS(newSettings)
+		if err != nil {
+			return nil, err
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		restClient.Transport = transport
+	}
+
+	publisher := httpPublisher{restClient: restClient, settings: newSettings}
+	if newSettings.BatchAddress != "" {
+		return httpBatchPublisher{publisher}, nil
+	}
+	return publisher, nil
+}
+
+func loadTLS(settings HTTPSettings) (*tls.Config, error) {
+	config := &tls.Config{}
+	if settings.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(settings.CertFile, settings.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %s", err.Error())
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	if settings.CAFile != "" {
+		bundle, err := ioutil.ReadFile(settings.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load CA bundle: %s", err.Error())
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", settings.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
+
+// failedPublisher is returned when a publisher could not be set up
+type failedPublisher struct {
+	err error
+}
+
+func (publisher failedPublisher) Publish([]byte) error {
+	return publisher.err
 }
@@ -0,0 +1,210 @@
+package publishers
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// statusServer responds to each request with the next status, the last status repeats once they are used up
+type statusServer struct {
+	mutex    sync.Mutex
+	statuses []int
+	requests int
+}
+
+func (s *statusServer) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	status := s.statuses[len(s.statuses)-1]
+	if s.requests < len(s.statuses) {
+		status = s.statuses[s.requests]
+	}
+	s.requests++
+	w.WriteHeader(status)
+}
+
+func TestHTTPRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []int
+		maxAttempts int
+		budget      *RetryBudget
+		requests    int
+		failed      bool
+	}{
+		{name: "success", statuses: []int{http.StatusOK}, maxAttempts: 3, requests: 1},
+		{name: "any 2xx", statuses: []int{http.StatusAccepted}, maxAttempts: 3, requests: 1},
+		{name: "no retries by default", statuses: []int{http.StatusServiceUnavailable}, requests: 1, failed: true},
+		{name: "retry 5xx", statuses: []int{http.StatusBadGateway, http.StatusInternalServerError, http.StatusOK}, maxAttempts: 3, requests: 3},
+		{name: "give up after max attempts", statuses: []int{http.StatusServiceUnavailable}, maxAttempts: 3, requests: 3, failed: true},
+		{name: "4xx is not retried", statuses: []int{http.StatusBadRequest}, maxAttempts: 3, requests: 1, failed: true},
+		{name: "budget used up", statuses: []int{http.StatusServiceUnavailable}, maxAttempts: 3, budget: NewRetryBudget(1, 0), requests: 2, failed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &statusServer{statuses: test.statuses}
+			httpServer := httptest.NewServer(server)
+			defer httpServer.Close()
+
+			publisher := SetupHTTP(HTTPSettings{
+				Address:     httpServer.URL,
+				MaxAttempts: test.maxAttempts,
+				RetryBudget: test.budget,
+				BaseDelay:   time.Millisecond,
+				MaxDelay:    time.Millisecond,
+			})
+
+			err := publisher.Publish([]byte(`{}`))
+			if (err != nil) != test.failed {
+				t.Errorf("error is %v, expected failed to be %v", err, test.failed)
+			}
+			if server.requests != test.requests {
+				t.Errorf("sent %d requests, expected %d", server.requests, test.requests)
+			}
+		})
+	}
+}
+
+func TestHTTPDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		attempt int
+		limit   time.Duration
+	}{
+		{name: "first retry", attempt: 1, limit: 10 * time.Millisecond},
+		{name: "doubles", attempt: 3, limit: 40 * time.Millisecond},
+		{name: "capped", attempt: 10, limit: 50 * time.Millisecond},
+	}
+
+	publisher := httpPublisher{settings: HTTPSettings{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				if delay := publisher.delay(test.attempt); delay <= 0 || delay > test.limit {
+					t.Fatalf("delay is %s, expected it to be within (0, %s]", delay, test.limit)
+				}
+			}
+		})
+	}
+}
+
+// bodyServer records the path and body of each request
+type bodyServer struct {
+	mutex    sync.Mutex
+	requests []string
+}
+
+func (s *bodyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests = append(s.requests, r.URL.Path+" "+string(body))
+}
+
+func TestHTTPBatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		batch    bool
+		messages [][]byte
+		expected []string
+	}{
+		{name: "batch endpoint", batch: true, messages: [][]byte{[]byte(`{"a":1}`), []byte(`{"b":2}`)}, expected: []string{`/batch [{"a":1},{"b":2}]`}},
+		{name: "single message", batch: true, messages: [][]byte{[]byte(`{"a":1}`)}, expected: []string{`/batch [{"a":1}]`}},
+		{name: "no batch endpoint", messages: [][]byte{[]byte(`{"a":1}`), []byte(`{"b":2}`)}, expected: []string{`/log {"a":1}`, `/log {"b":2}`}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &bodyServer{}
+			httpServer := httptest.NewServer(server)
+			defer httpServer.Close()
+
+			settings := HTTPSettings{Address: httpServer.URL + "/log"}
+			if test.batch {
+				settings.BatchAddress = httpServer.URL + "/batch"
+			}
+			publisher := SetupHTTP(settings)
+
+			if batcher, ok := publisher.(BatchPublisher); ok != test.batch {
+				t.Fatalf("batch publisher is %v, expected %v", ok, test.batch)
+			} else if ok {
+				if err := batcher.PublishBatch(test.messages); err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			} else {
+				for _, message := range test.messages {
+					if err := publisher.Publish(message); err != nil {
+						t.Fatalf("unexpected error: %s", err)
+					}
+				}
+			}
+
+			if !reflect.DeepEqual(server.requests, test.expected) {
+				t.Errorf("requests are %v, expected %v", server.requests, test.expected)
+			}
+		})
+	}
+}
+
+func TestHTTPRequestInterceptor(t *testing.T) {
+	tests := []struct {
+		name        string
+		interceptor func(request *http.Request) error
+		requests    []string
+		failed      bool
+	}{
+		{
+			name: "change the request",
+			interceptor: func(request *http.Request) error {
+				request.URL.Path = "/signed"
+				return nil
+			},
+			requests: []string{"/signed {}"},
+		},
+		{
+			name:        "rejected requests are not sent or retried",
+			interceptor: func(*http.Request) error { return errors.New("unable to sign") },
+			requests:    nil,
+			failed:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &bodyServer{}
+			httpServer := httptest.NewServer(server)
+			defer httpServer.Close()
+
+			calls := 0
+			publisher := SetupHTTP(HTTPSettings{
+				Address:     httpServer.URL + "/log",
+				MaxAttempts: 3,
+				BaseDelay:   time.Millisecond,
+				RequestInterceptor: func(request *http.Request) error {
+					calls++
+					return test.interceptor(request)
+				},
+			})
+
+			err := publisher.Publish([]byte(`{}`))
+			if (err != nil) != test.failed {
+				t.Errorf("error is %v, expected failed to be %v", err, test.failed)
+			}
+			if calls != 1 {
+				t.Errorf("interceptor called %d times, expected 1", calls)
+			}
+			if !reflect.DeepEqual(server.requests, test.requests) {
+				t.Errorf("requests are %v, expected %v", server.requests, test.requests)
+			}
+		})
+	}
+}
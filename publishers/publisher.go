@@ -5,3 +5,15 @@ type Publisher interface {
 	// Publish message
 	Publish(messageBites []byte) error
 }
+
+// Retrier is implemented by publishers that retry failed publishes themselves, the logger does not retry them again
+type Retrier interface {
+	// Retries is true if the publisher retries failed publishes
+	Retries() bool
+}
+
+// BatchPublisher is implemented by publishers that can send several messages at once
+type BatchPublisher interface {
+	// PublishBatch messages
+	PublishBatch(messages [][]byte) error
+}
@@ -2,6 +2,7 @@ package publishers
 
 import (
 	"context"
+	"io"
 
 	"github.com/cjburchell/pubsub"
 )
@@ -15,6 +16,15 @@ func (publisher pubSubPublisher) Publish(messageBites []byte) error {
 	return publisher.connection.Publish(context.Background(), "logs", messageBites)
 }
 
+// Close the connection if the pub sub provider implements io.Closer. None of the providers in pubsub v1.2.19
+// do, so for them this does nothing and the connection is neither drained nor closed.
+func (publisher pubSubPublisher) Close() error {
+	if closer, ok := publisher.connection.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
 // SetupPubSub connection
 func SetupPubSub(newSettings pubsub.Settings) (Publisher, error) {
 	connection, err := pubsub.Create(context.Background(), newSettings)
@@ -0,0 +1,264 @@
+package publishers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults used by the syslog and journald publishers
+const (
+	// DefaultSyslogStructuredDataID is the SD-ID the message fields are sent under, 32473 is the enterprise
+	// number reserved for examples so set your own if you have one
+	DefaultSyslogStructuredDataID = "fields@32473"
+	// DefaultJournaldSocket is the socket of the journald native protocol
+	DefaultJournaldSocket = "/run/systemd/journal/socket"
+)
+
+// Syslog facilities
+const (
+	SyslogFacilityUser   = 1
+	SyslogFacilityDaemon = 3
+	SyslogFacilityLocal0 = 16
+)
+
+// SyslogSettings struct
+type SyslogSettings struct {
+	// Network is udp, tcp, unix or unixgram, empty sends to the local syslog daemon
+	Network string
+	// Address of the syslog server, not used for the local syslog daemon
+	Address string
+	// AppName the messages are sent with, defaults to the service name of each message
+	AppName string
+	// Facility of the messages, defaults to SyslogFacilityUser
+	Facility int
+	// StructuredDataID is the SD-ID the message fields are sent under, defaults to DefaultSyslogStructuredDataID
+	StructuredDataID string
+}
+
+// JournaldSettings struct
+type JournaldSettings struct {
+	// Socket of the journal, defaults to DefaultJournaldSocket
+	Socket string
+	// Identifier is the SYSLOG_IDENTIFIER of the entries, defaults to the service name of each message
+	Identifier string
+}
+
+// syslogMessage is the part of the message the syslog and journald publishers need
+type syslogMessage struct {
+	ID          string                 `json:"id"`
+	Text        string                 `json:"text"`
+	ServiceName string                 `json:"serviceName"`
+	Time        int64                  `json:"time"`
+	Hostname    string                 `json:"hostname"`
+	File        string                 `json:"file"`
+	Line        int                    `json:"line"`
+	Func        string                 `json:"func"`
+	Fields      map[string]interface{} `json:"fields"`
+	Level       struct {
+		Text     string `json:"Text"`
+		Severity int    `json:"Severity"`
+	} `json:"level"`
+}
+
+func decodeSyslogMessage(messageBites []byte) (syslogMessage, error) {
+	var message syslogMessage
+	err := json.Unmarshal(messageBites, &message)
+	return message, err
+}
+
+// syslogSeverity maps the severity of a level to a syslog severity the same way as Level.ToSyslog
+func syslogSeverity(severity int) int {
+	switch {
+	case severity >= 4:
+		return 2 // critical
+	case severity >= 3:
+		return 3 // error
+	case severity >= 2:
+		return 4 // warning
+	case severity >= 1:
+		return 6 // informational
+	default:
+		return 7 // debug
+	}
+}
+
+// flattenFields joins nested field names with dots
+func flattenFields(prefix string, fields map[string]interface{}, flat map[string]string) {
+	for key, value := range fields {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch value := value.(type) {
+		case map[string]interface{}:
+			flattenFields(key, value, flat)
+		case string:
+			flat[key] = value
+		case nil:
+			flat[key] = ""
+		default:
+			data, err := json.Marshal(value)
+			if err != nil {
+				flat[key] = fmt.Sprint(value)
+			} else {
+				flat[key] = string(data)
+			}
+		}
+	}
+}
+
+// sortedFields flattens the fields and sorts their names so the output is stable
+func sortedFields(fields map[string]interface{}) ([]string, map[string]string) {
+	flat := map[string]string{}
+	flattenFields("", fields, flat)
+
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, flat
+}
+
+// syslogToken is a header field of RFC 5424, printable ascii without spaces or the nil value "-"
+func syslogToken(value string, max int) string {
+	token := strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, value)
+
+	if len(token) > max {
+		token = token[:max]
+	}
+	if token == "" {
+		return "-"
+	}
+	return token
+}
+
+// syslogParamName is a structured data parameter name, it can not have '=', ']', '"' or spaces
+func syslogParamName(name string) string {
+	return syslogToken(strings.Map(func(r rune) rune {
+		if r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, name), 32)
+}
+
+var syslogParamEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// formatRFC5424 formats the message as an RFC 5424 syslog message
+func formatRFC5424(message syslogMessage, settings SyslogSettings) []byte {
+	facility := settings.Facility
+	if facility <= 0 {
+		facility = SyslogFacilityUser
+	}
+
+	appName := settings.AppName
+	if appName == "" {
+		appName = message.ServiceName
+	}
+
+	sdID := settings.StructuredDataID
+	if sdID == "" {
+		sdID = DefaultSyslogStructuredDataID
+	}
+
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "<%d>1 %s %s %s %d - ",
+		facility*8+syslogSeverity(message.Level.Severity),
+		time.Unix(0, message.Time*int64(time.Millisecond)).UTC().Format(time.RFC3339Nano),
+		syslogToken(message.Hostname, 255),
+		syslogToken(appName, 48),
+		os.Getpid())
+
+	// the message id is a unique id rather than the type of message that MSGID is for so it is sent as structured data
+	keys, fields := sortedFields(message.Fields)
+	if len(keys) == 0 && message.ID == "" {
+		buffer.WriteString("-")
+	} else {
+		buffer.WriteString("[" + sdID)
+		if message.ID != "" {
+			fmt.Fprintf(&buffer, ` id="%s"`, syslogParamEscaper.Replace(message.ID))
+		}
+		for _, key := range keys {
+			fmt.Fprintf(&buffer, ` %s="%s"`, syslogParamName(key), syslogParamEscaper.Replace(fields[key]))
+		}
+		buffer.WriteString("]")
+	}
+
+	buffer.WriteString(" " + message.Text)
+	return buffer.Bytes()
+}
+
+// journaldName is a journal field name, upper case letters, digits and underscores not starting with an underscore
+func journaldName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+
+	name = strings.TrimLeft(name, "_0123456789")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// writeJournaldField writes a field of the journald native protocol, values with new lines are sent with their length
+func writeJournaldField(buffer *bytes.Buffer, name string, value string) {
+	if !strings.Contains(value, "\n") {
+		buffer.WriteString(name + "=" + value + "\n")
+		return
+	}
+
+	buffer.WriteString(name + "\n")
+	_ = binary.Write(buffer, binary.LittleEndian, uint64(len(value)))
+	buffer.WriteString(value + "\n")
+}
+
+// formatJournald formats the message as a journal entry, the message fields are sent as journal fields
+func formatJournald(message syslogMessage, settings JournaldSettings) []byte {
+	identifier := settings.Identifier
+	if identifier == "" {
+		identifier = message.ServiceName
+	}
+
+	var buffer bytes.Buffer
+	writeJournaldField(&buffer, "MESSAGE", message.Text)
+	writeJournaldField(&buffer, "PRIORITY", strconv.Itoa(syslogSeverity(message.Level.Severity)))
+	writeJournaldField(&buffer, "SYSLOG_IDENTIFIER", identifier)
+	if message.ID != "" {
+		writeJournaldField(&buffer, "MESSAGE_ID_UATU", message.ID)
+	}
+	if message.File != "" {
+		writeJournaldField(&buffer, "CODE_FILE", message.File)
+		writeJournaldField(&buffer, "CODE_LINE", strconv.Itoa(message.Line))
+		writeJournaldField(&buffer, "CODE_FUNC", message.Func)
+	}
+
+	keys, fields := sortedFields(message.Fields)
+	for _, key := range keys {
+		if name := journaldName(key); name != "" {
+			writeJournaldField(&buffer, name, fields[key])
+		}
+	}
+
+	return buffer.Bytes()
+}
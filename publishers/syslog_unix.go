@@ -0,0 +1,146 @@
+//go:build !windows
+// +build !windows
+
+package publishers
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+)
+
+// localSyslogSockets are where the local syslog daemon is looked for
+var localSyslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// socketPublisher writes each message to a socket, reconnecting once if a write fails
+type socketPublisher struct {
+	mutex   sync.Mutex
+	dial    func() (net.Conn, error)
+	conn    net.Conn
+	format  func(message syslogMessage) []byte
+	framing func(data []byte) []byte
+	closed  bool
+}
+
+// SetupSyslog sets up a publisher that sends each message to syslog in the RFC 5424 format
+// with the message fields as structured data
+func SetupSyslog(settings SyslogSettings) (Publisher, error) {
+	publisher := &socketPublisher{
+		format: func(message syslogMessage) []byte { return formatRFC5424(message, settings) },
+	}
+
+	switch settings.Network {
+	case "":
+		publisher.dial = dialLocalSyslog
+	case "tcp", "tcp4", "tcp6":
+		// messages over tcp are framed by octet counting from RFC 6587
+		publisher.framing = func(data []byte) []byte { return append([]byte(strconv.Itoa(len(data))+" "), data...) }
+		publisher.dial = func() (net.Conn, error) { return net.Dial(settings.Network, settings.Address) }
+	case "unix":
+		publisher.framing = func(data []byte) []byte { return append(data, '\n') }
+		publisher.dial = func() (net.Conn, error) { return net.Dial(settings.Network, settings.Address) }
+	case "udp", "udp4", "udp6", "unixgram":
+		publisher.dial = func() (net.Conn, error) { return net.Dial(settings.Network, settings.Address) }
+	default:
+		return nil, fmt.Errorf("unknown syslog network %s", settings.Network)
+	}
+
+	if err := publisher.connect(); err != nil {
+		return nil, err
+	}
+
+	return publisher, nil
+}
+
+// SetupJournald sets up a publisher that sends each message to systemd-journald with the message fields as journal fields
+func SetupJournald(settings JournaldSettings) (Publisher, error) {
+	socket := settings.Socket
+	if socket == "" {
+		socket = DefaultJournaldSocket
+	}
+
+	publisher := &socketPublisher{
+		format: func(message syslogMessage) []byte { return formatJournald(message, settings) },
+		dial:   func() (net.Conn, error) { return net.Dial("unixgram", socket) },
+	}
+
+	if err := publisher.connect(); err != nil {
+		return nil, err
+	}
+
+	return publisher, nil
+}
+
+func dialLocalSyslog() (net.Conn, error) {
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, path := range localSyslogSockets {
+			if conn, err := net.Dial(network, path); err == nil {
+				return conn, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("unable to connect to the local syslog daemon")
+}
+
+func (publisher *socketPublisher) connect() error {
+	conn, err := publisher.dial()
+	if err != nil {
+		return err
+	}
+
+	publisher.conn = conn
+	return nil
+}
+
+// Publish message
+func (publisher *socketPublisher) Publish(messageBites []byte) error {
+	message, err := decodeSyslogMessage(messageBites)
+	if err != nil {
+		return err
+	}
+
+	data := publisher.format(message)
+	if publisher.framing != nil {
+		data = publisher.framing(data)
+	}
+
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+
+	if publisher.closed {
+		return fmt.Errorf("publisher is closed")
+	}
+
+	if publisher.conn != nil {
+		if _, err = publisher.conn.Write(data); err == nil {
+			return nil
+		}
+		_ = publisher.conn.Close()
+		publisher.conn = nil
+	}
+
+	if err := publisher.connect(); err != nil {
+		return err
+	}
+
+	_, err = publisher.conn.Write(data)
+	return err
+}
+
+// Close the connection
+func (publisher *socketPublisher) Close() error {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+
+	if publisher.closed {
+		return nil
+	}
+
+	publisher.closed = true
+	if publisher.conn == nil {
+		return nil
+	}
+	return publisher.conn.Close()
+}
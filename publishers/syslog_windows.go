@@ -0,0 +1,16 @@
+//go:build windows
+// +build windows
+
+package publishers
+
+import "fmt"
+
+// SetupSyslog sets up the syslog publisher, on windows it returns an error
+func SetupSyslog(SyslogSettings) (Publisher, error) {
+	return nil, fmt.Errorf("unable to log to syslog, syslog is not supported on windows")
+}
+
+// SetupJournald sets up the journald publisher, on windows it returns an error
+func SetupJournald(JournaldSettings) (Publisher, error) {
+	return nil, fmt.Errorf("unable to log to journald, journald is not supported on windows")
+}
@@ -0,0 +1,68 @@
+package log
+
+import (
+	"fmt"
+	"time"
+)
+
+// Fields added to query messages
+const (
+	QueryStatementField = "db.statement"
+	QueryDurationField  = "db.duration_ms"
+	QueryArgsField      = "db.args"
+	QueryErrorField     = "db.error"
+	QueryRowsField      = "db.rows"
+)
+
+// queryLogger is implemented by the loggers in this package so Query can use their settings
+type queryLogger interface {
+	query(sql string, args []interface{}, dur time.Duration, err error, rows []int64)
+}
+
+// Query logs a database query and how long it took. Successful queries are logged at debug level,
+// queries slower than Settings.SlowQueryThreshold at warning level and failed queries at error level.
+// The query arguments are only included when Settings.LogQueryArgs is set. Loggers from other packages
+// get the query without the arguments and without the slow query check. The number of rows the query returned
+// or changed can be given after the error, it is logged in QueryRowsField.
+func Query(l ILog, sql string, args []interface{}, dur time.Duration, err error, rows ...int64) {
+	if q, ok := unwrap(l).(queryLogger); ok {
+		q.query(sql, args, dur, err, rows)
+		return
+	}
+
+	text, level, fields := queryMessage(Settings{}, sql, args, dur, err, rows)
+	l.WithFields(fields).Log(level, text)
+}
+
+func (l logger) query(sql string, args []interface{}, dur time.Duration, err error, rows []int64) {
+	text, level, fields := queryMessage(l.settings, sql, args, dur, err, rows)
+	l.printFieldsLog(text, level, fields)
+}
+
+// queryMessage gets the text, level and fields a query is logged with
+func queryMessage(settings Settings, sql string, args []interface{}, dur time.Duration, err error, rows []int64) (string, Level, map[string]interface{}) {
+	fields := map[string]interface{}{
+		QueryStatementField: sql,
+		QueryDurationField:  float64(dur) / float64(time.Millisecond),
+	}
+
+	if settings.LogQueryArgs && len(args) != 0 {
+		fields[QueryArgsField] = args
+	}
+
+	took := dur.String()
+	if len(rows) != 0 {
+		fields[QueryRowsField] = rows[0]
+		took = fmt.Sprintf("%s (%d rows)", dur, rows[0])
+	}
+
+	switch {
+	case err != nil:
+		fields[QueryErrorField] = err
+		return fmt.Sprintf("Query failed after %s: %s", took, sql), ERROR, fields
+	case settings.SlowQueryThreshold > 0 && dur >= settings.SlowQueryThreshold:
+		return fmt.Sprintf("Slow query took %s: %s", took, sql), WARNING, fields
+	default:
+		return fmt.Sprintf("Query took %s: %s", took, sql), DEBUG, fields
+	}
+}
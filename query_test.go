@@ -0,0 +1,47 @@
+package log
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		dur      time.Duration
+		err      error
+		rows     []int64
+		level    Level
+		text     string
+		hasRows  bool
+		expected int64
+	}{
+		{name: "success", dur: time.Millisecond, level: DEBUG, text: "Query took 1ms: select 1"},
+		{name: "rows", dur: time.Millisecond, rows: []int64{5}, level: DEBUG, text: "Query took 1ms (5 rows): select 1", hasRows: true, expected: 5},
+		{name: "slow", dur: time.Second, rows: []int64{0}, level: WARNING, text: "Slow query took 1s (0 rows): select 1", hasRows: true},
+		{name: "failed", dur: time.Millisecond, err: errors.New("boom"), level: ERROR, text: "Query failed after 1ms: select 1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewMemoryLogger(Settings{MinLogLevel: TRACE, SlowQueryThreshold: 100 * time.Millisecond})
+			Query(m, "select 1", nil, test.dur, test.err, test.rows...)
+
+			messages := m.Messages()
+			if len(messages) != 1 {
+				t.Fatalf("logged %d messages, expected 1", len(messages))
+			}
+
+			message := messages[0]
+			if message.Level != test.level || message.Text != test.text {
+				t.Errorf("logged %q at %s, expected %q at %s", message.Text, message.Level.Text, test.text, test.level.Text)
+			}
+
+			rows, ok := message.Fields[QueryRowsField]
+			if ok != test.hasRows || (ok && rows != test.expected) {
+				t.Errorf("rows field is %v, expected %d", rows, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package log
+
+import "sync"
+
+// OverflowPolicy is what happens when a message is logged while the async buffer is full
+type OverflowPolicy string
+
+const (
+	// DropOldest drops the oldest message in the buffer to make room, it is the default
+	DropOldest OverflowPolicy = "dropOldest"
+	// DropNewest drops the message being logged
+	DropNewest OverflowPolicy = "dropNewest"
+	// Block waits for room in the buffer
+	Block OverflowPolicy = "block"
+)
+
+// DefaultBufferSize is the number of messages buffered in async mode when Settings.BufferSize is not set
+const DefaultBufferSize = 1000
+
+// dropOverflow is the drop reason for messages dropped because the async buffer was full
+const dropOverflow = "overflow"
+
+// queue buffers entries for the background publishing goroutine
+type queue struct {
+	mutex   sync.Mutex
+	changed *sync.Cond
+	jobs    [][]entry
+	entries int
+	size    int
+	policy  OverflowPolicy
+	busy    int
+	paused  bool
+	closed  bool
+	workers sync.WaitGroup
+}
+
+func newQueue(size int, policy OverflowPolicy) *queue {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+
+	q := &queue{size: size, policy: policy}
+	q.changed = sync.NewCond(&q.mutex)
+	return q
+}
+
+// push adds the entries to the queue, false is returned if the queue is closed. The size limits the number of
+// entries rather than jobs so a batch takes up as much room as its entries, a batch bigger than the whole queue
+// is only added once the queue is empty.
+func (q *queue) push(job []entry) (pushed bool, dropped int) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for !q.closed && q.entries != 0 && q.entries+len(job) > q.size {
+		switch q.policy {
+		case Block:
+			q.changed.Wait()
+			continue
+		case DropNewest:
+			return true, len(job)
+		}
+
+		dropped += len(q.jobs[0])
+		q.entries -= len(q.jobs[0])
+		q.jobs = q.jobs[1:]
+	}
+
+	if q.closed {
+		return false, dropped
+	}
+
+	q.jobs = append(q.jobs, job)
+	q.entries += len(job)
+	q.changed.Broadcast()
+	return true, dropped
+}
+
+// pop waits for the next entries, false is returned once the queue is closed and empty.
+// done is true if the worker finished publishing the last entries it popped.
+func (q *queue) pop(done bool) ([]entry, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if done {
+		q.busy--
+		q.changed.Broadcast()
+	}
+
+	// once closed the queue is emptied even if it is paused
+	for len(q.jobs) == 0 || (q.paused && !q.closed) {
+		if q.closed {
+			return nil, false
+		}
+		q.changed.Wait()
+	}
+
+	job := q.jobs[0]
+	q.jobs = q.jobs[1:]
+	q.entries -= len(job)
+	q.busy++
+	q.changed.Broadcast()
+	return job, true
+}
+
+// depth is the number of entries waiting to be published
+func (q *queue) depth() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.entries
+}
+
+// flush waits until everything in the queue has been published, while paused it only waits for
+// the publishes in progress
+func (q *queue) flush() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for (len(q.jobs) != 0 && !q.paused) || q.busy != 0 {
+		q.changed.Wait()
+	}
+}
+
+// pause stops the workers taking entries and waits for the publishes in progress to finish
+func (q *queue) pause() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.paused = true
+	for q.busy != 0 {
+		q.changed.Wait()
+	}
+}
+
+// resume lets the workers take entries again
+func (q *queue) resume() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.paused = false
+	q.changed.Broadcast()
+}
+
+// close stops taking new entries and waits for the queued entries to be published
+func (q *queue) close() {
+	q.mutex.Lock()
+	q.closed = true
+	q.changed.Broadcast()
+	q.mutex.Unlock()
+
+	q.workers.Wait()
+}
+
+// start runs the workers that publish the queued entries, with more than one worker
+// the messages may be published out of order
+func (l logger) start(q *queue, workers int) {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	q.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go l.run(q)
+	}
+}
+
+// run publishes the queued entries until the queue is closed
+func (l logger) run(q *queue) {
+	defer q.workers.Done()
+	for done := false; ; done = true {
+		job, ok := q.pop(done)
+		if !ok {
+			return
+		}
+
+		if len(job) == 1 {
+			l.publish(job[0])
+		} else {
+			l.publishBatch(job)
+		}
+	}
+}
+
+// deliver publishes the entries, in async mode they are queued for the background goroutine
+// and when batching they are collected until the batch is full
+func (l logger) deliver(job ...entry) {
+	if l.batcher != nil {
+		if job = l.batcher.add(job); job == nil {
+			return
+		}
+	}
+
+	l.enqueue(job)
+}
+
+func (l logger) enqueue(job []entry) {
+	if l.queue != nil {
+		pushed, dropped := l.queue.push(job)
+		for i := 0; i < dropped; i++ {
+			l.dropped(dropOverflow)
+		}
+
+		if pushed {
+			return
+		}
+	}
+
+	if len(job) == 1 {
+		l.publish(job[0])
+	} else {
+		l.publishBatch(job)
+	}
+}
+
+// Flush waits until all the buffered and batched messages have been published, messages held by Pause stay held
+// until Resume or Close is called
+func (l logger) Flush() {
+	l.flushBatch(false)
+	if l.queue != nil {
+		l.queue.flush()
+	}
+}
@@ -0,0 +1,127 @@
+package log
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testJob(texts ...string) []entry {
+	job := make([]entry, len(texts))
+	for i, text := range texts {
+		job[i] = entry{message: Message{Text: text}}
+	}
+	return job
+}
+
+func TestQueueOverflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		policy   OverflowPolicy
+		jobs     [][]entry
+		dropped  int
+		expected []string
+	}{
+		{
+			name:     "drop oldest",
+			size:     2,
+			policy:   DropOldest,
+			jobs:     [][]entry{testJob("1"), testJob("2"), testJob("3")},
+			dropped:  1,
+			expected: []string{"2", "3"},
+		},
+		{
+			name:     "drop newest",
+			size:     2,
+			policy:   DropNewest,
+			jobs:     [][]entry{testJob("1"), testJob("2"), testJob("3")},
+			dropped:  1,
+			expected: []string{"1", "2"},
+		},
+		{
+			name:     "batches count each entry",
+			size:     3,
+			policy:   DropOldest,
+			jobs:     [][]entry{testJob("1", "2"), testJob("3", "4")},
+			dropped:  2,
+			expected: []string{"3", "4"},
+		},
+		{
+			name:     "batch dropped as a whole",
+			size:     3,
+			policy:   DropNewest,
+			jobs:     [][]entry{testJob("1", "2"), testJob("3", "4")},
+			dropped:  2,
+			expected: []string{"1", "2"},
+		},
+		{
+			name:     "batch bigger than the queue",
+			size:     2,
+			policy:   DropOldest,
+			jobs:     [][]entry{testJob("1"), testJob("2", "3", "4")},
+			dropped:  1,
+			expected: []string{"2", "3", "4"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q := newQueue(test.size, test.policy)
+			dropped := 0
+			for _, job := range test.jobs {
+				pushed, count := q.push(job)
+				if !pushed {
+					t.Fatal("push failed on an open queue")
+				}
+				dropped += count
+			}
+
+			if dropped != test.dropped {
+				t.Errorf("dropped %d entries, expected %d", dropped, test.dropped)
+			}
+
+			if depth := q.depth(); depth != len(test.expected) {
+				t.Errorf("depth is %d, expected %d", depth, len(test.expected))
+			}
+
+			q.close()
+			var texts []string
+			for job, ok := q.pop(false); ok; job, ok = q.pop(true) {
+				for _, e := range job {
+					texts = append(texts, e.message.Text)
+				}
+			}
+
+			if !reflect.DeepEqual(texts, test.expected) {
+				t.Errorf("queued %v, expected %v", texts, test.expected)
+			}
+		})
+	}
+}
+
+func TestQueueBlock(t *testing.T) {
+	q := newQueue(1, Block)
+	q.push(testJob("1"))
+
+	pushed := make(chan struct{})
+	go func() {
+		defer close(pushed)
+		q.push(testJob("2"))
+	}()
+
+	select {
+	case <-pushed:
+		t.Fatal("push did not wait for room in the queue")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if job, ok := q.pop(false); !ok || job[0].message.Text != "1" {
+		t.Fatalf("popped %v, expected the first entry", job)
+	}
+	<-pushed
+
+	if depth := q.depth(); depth != 1 {
+		t.Errorf("depth is %d, expected 1", depth)
+	}
+}
@@ -0,0 +1,69 @@
+package log
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicField is the field the recovered panic value is logged in
+const PanicField = "panic"
+
+// RecoverAndLog recovers a panic and logs it as fatal with the goroutine's stack, then waits for the logger
+// to publish its buffered messages. With repanic the panic continues once it has been logged.
+// It must be deferred directly e.g. defer log.RecoverAndLog(logger, true)
+func RecoverAndLog(l ILog, repanic bool) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	logPanic(l, r, debug.Stack())
+	if repanic {
+		panic(r)
+	}
+}
+
+// CapturePanic runs f logging any panic it has like RecoverAndLog, true is returned if f panicked
+func CapturePanic(l ILog, f func(), repanic bool) (panicked bool) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		panicked = true
+		logPanic(l, r, debug.Stack())
+		if repanic {
+			panic(r)
+		}
+	}()
+
+	f()
+	return false
+}
+
+// fatalLogger is implemented by the loggers in this package so a fatal message can be logged without panicking
+type fatalLogger interface {
+	logFatal(text string, fields map[string]interface{})
+}
+
+func logPanic(l ILog, value interface{}, stack []byte) {
+	text := fmt.Sprintf("Panic: %v\n%s", value, stack)
+	fatal(l, text, map[string]interface{}{PanicField: fmt.Sprint(value)})
+	l.Flush()
+}
+
+// fatal logs a fatal message without panicking
+func fatal(l ILog, text string, fields map[string]interface{}) {
+	if fl, ok := l.(fatalLogger); ok {
+		fl.logFatal(text, fields)
+		return
+	}
+
+	l = l.WithFields(fields)
+	recoverFatal(func() { l.Fatal(nil, text) })
+}
+
+func (l logger) logFatal(text string, fields map[string]interface{}) {
+	l.printFieldsLog(text, FATAL, fields)
+}
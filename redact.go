@@ -0,0 +1,185 @@
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// RedactedValue replaces the value of struct fields tagged with `log:"redact"`
+const RedactedValue = "[REDACTED]"
+
+const (
+	redactTag      = "redact"
+	maxRedactDepth = 32
+)
+
+// redactTypes caches if a type contains any fields that need to be redacted
+var redactTypes sync.Map
+
+func isRedacted(field reflect.StructField) bool {
+	for _, option := range strings.Split(field.Tag.Get("log"), ",") {
+		if strings.TrimSpace(option) == redactTag {
+			return true
+		}
+	}
+	return false
+}
+
+func elemType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+func needsRedaction(t reflect.Type) bool {
+	t = elemType(t)
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	if cached, ok := redactTypes.Load(t); ok {
+		return cached.(bool)
+	}
+
+	result := hasRedactedFields(t, map[reflect.Type]bool{})
+	redactTypes.Store(t, result)
+	return result
+}
+
+func hasRedactedFields(t reflect.Type, seen map[reflect.Type]bool) bool {
+	t = elemType(t)
+	if t.Kind() != reflect.Struct || seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		if isRedacted(field) || hasRedactedFields(field.Type, seen) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jsonName gets the name the field is serialized with, false if it is not serialized at all
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name, true
+	}
+
+	return field.Name, true
+}
+
+// redact copies the value replacing any fields tagged with `log:"redact"`,
+// values that have no tagged fields are returned unchanged
+func redact(value interface{}) (interface{}, bool) {
+	if value == nil || !needsRedaction(reflect.TypeOf(value)) {
+		return value, false
+	}
+
+	return redactValue(reflect.ValueOf(value), 0), true
+}
+
+func redactValue(v reflect.Value, depth int) interface{} {
+	if depth > maxRedactDepth {
+		return RedactedValue
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem(), depth+1)
+	case reflect.Struct:
+		if !needsRedaction(v.Type()) {
+			return v.Interface()
+		}
+		result := map[string]interface{}{}
+		redactStruct(v, result, depth)
+		return result
+	case reflect.Slice, reflect.Array:
+		if !needsRedaction(v.Type()) || (v.Kind() == reflect.Slice && v.IsNil()) {
+			return v.Interface()
+		}
+		result := make([]interface{}, v.Len())
+		for i := range result {
+			result[i] = redactValue(v.Index(i), depth+1)
+		}
+		return result
+	case reflect.Map:
+		if !needsRedaction(v.Type()) || v.IsNil() {
+			return v.Interface()
+		}
+		result := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			result[fmt.Sprint(iter.Key().Interface())] = redactValue(iter.Value(), depth+1)
+		}
+		return result
+	}
+
+	if !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func redactStruct(v reflect.Value, result map[string]interface{}, depth int) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		name, ok := jsonName(field)
+		if !ok {
+			continue
+		}
+
+		value := v.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" && elemType(field.Type).Kind() == reflect.Struct {
+			for value.Kind() == reflect.Ptr {
+				if value.IsNil() {
+					break
+				}
+				value = value.Elem()
+			}
+
+			if value.Kind() == reflect.Struct {
+				redactStruct(value, result, depth+1)
+				continue
+			}
+		}
+
+		if !value.CanInterface() {
+			continue
+		}
+
+		if isRedacted(field) {
+			result[name] = RedactedValue
+			continue
+		}
+
+		result[name] = redactValue(value, depth+1)
+	}
+}
@@ -0,0 +1,112 @@
+package log
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type redactCredentials struct {
+	User     string `json:"user"`
+	Password string `json:"password" log:"redact"`
+}
+
+type redactEmbedded struct {
+	redactCredentials
+	Host string `json:"host"`
+}
+
+type redactNested struct {
+	Name    string                        `json:"name"`
+	Login   *redactCredentials            `json:"login,omitempty"`
+	Logins  []redactCredentials           `json:"logins"`
+	ByHost  map[string]*redactCredentials `json:"by_host"`
+	Ignored string                        `json:"-" log:"redact"`
+	Token   string                        `log:"other, redact"`
+	secret  string
+}
+
+type redactPlain struct {
+	Password string `json:"password"`
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		redacted bool
+		expected string
+	}{
+		{name: "nil", value: nil, redacted: false, expected: `null`},
+		{name: "no tags", value: redactPlain{Password: "secret"}, redacted: false, expected: `{"password":"secret"}`},
+		{name: "not a struct", value: []int{1, 2}, redacted: false, expected: `[1,2]`},
+		{
+			name:     "tagged field",
+			value:    redactCredentials{User: "bob", Password: "secret"},
+			redacted: true,
+			expected: `{"password":"[REDACTED]","user":"bob"}`,
+		},
+		{
+			name:     "pointer",
+			value:    &redactCredentials{User: "bob", Password: "secret"},
+			redacted: true,
+			expected: `{"password":"[REDACTED]","user":"bob"}`,
+		},
+		{
+			name:     "embedded",
+			value:    redactEmbedded{redactCredentials: redactCredentials{User: "bob", Password: "secret"}, Host: "db"},
+			redacted: true,
+			expected: `{"host":"db","password":"[REDACTED]","user":"bob"}`,
+		},
+		{
+			name: "nested",
+			value: redactNested{
+				Name:    "app",
+				Login:   &redactCredentials{User: "bob", Password: "secret"},
+				Logins:  []redactCredentials{{User: "sue", Password: "secret"}},
+				ByHost:  map[string]*redactCredentials{"db": {User: "ann", Password: "secret"}},
+				Ignored: "secret",
+				Token:   "secret",
+				secret:  "secret",
+			},
+			redacted: true,
+			expected: `{"Token":"[REDACTED]","by_host":{"db":{"password":"[REDACTED]","user":"ann"}},` +
+				`"login":{"password":"[REDACTED]","user":"bob"},"logins":[{"password":"[REDACTED]","user":"sue"}],"name":"app"}`,
+		},
+		{
+			name:     "slice of structs",
+			value:    []redactCredentials{{User: "bob", Password: "secret"}},
+			redacted: true,
+			expected: `[{"password":"[REDACTED]","user":"bob"}]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, redacted := redact(test.value)
+			if redacted != test.redacted {
+				t.Errorf("redacted is %v, expected %v", redacted, test.redacted)
+			}
+
+			data, err := json.Marshal(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != test.expected {
+				t.Errorf("result is %s, expected %s", data, test.expected)
+			}
+		})
+	}
+}
+
+func TestRedactFields(t *testing.T) {
+	m := NewMemoryLogger(Settings{MinLogLevel: INFO})
+	m.WithFields(map[string]interface{}{"login": redactCredentials{User: "bob", Password: "secret"}}).Print("logged in")
+
+	data, err := json.Marshal(m.Messages()[0].Fields["login"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"password":"[REDACTED]","user":"bob"}`; string(data) != expected {
+		t.Errorf("field is %s, expected %s", data, expected)
+	}
+}
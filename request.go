@@ -0,0 +1,110 @@
+package log
+
+import "sync"
+
+// DefaultRequestBufferSize is the number of messages a request logger holds when Settings.RequestBufferSize is not set
+const DefaultRequestBufferSize = 1000
+
+// dropRequest is the drop reason for messages that did not fit in a request logger's buffer
+const dropRequest = "request"
+
+// requestBuffer holds the messages logged during a request until it ends
+type requestBuffer struct {
+	mutex    sync.Mutex
+	size     int
+	messages []Message
+	ended    bool
+}
+
+// hold the message if the request has not ended, once the buffer is full the oldest message is dropped
+func (r *requestBuffer) hold(message Message) (held bool, dropped bool) {
+	if r == nil {
+		return false, false
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.ended {
+		return false, false
+	}
+
+	if len(r.messages) >= r.size {
+		r.messages = r.messages[1:]
+		dropped = true
+	}
+
+	r.messages = append(r.messages, message)
+	return true, dropped
+}
+
+// take the held messages, after the request has ended messages are no longer held
+func (r *requestBuffer) take(end bool) []Message {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	messages := r.messages
+	r.messages = nil
+	if end {
+		r.ended = true
+	}
+	return messages
+}
+
+// holdRequest holds the message if this is a request logger that has not ended
+func (l logger) holdRequest(message Message) bool {
+	held, dropped := l.request.hold(message)
+	if dropped {
+		l.dropped(dropRequest)
+	}
+	return held
+}
+
+// requestLogger is implemented by the loggers in this package that can hold the messages of a request
+type requestLogger interface {
+	beginRequest() (ILog, func(emit bool))
+}
+
+// BeginRequest creates a logger that holds its messages in memory until the returned end function is called.
+// Calling end(true), e.g. when the request failed, logs the held messages in order while end(false) discards them.
+// Fatal messages are never held, they log the held messages first. Messages logged after end are logged directly.
+// Loggers from other packages can not hold messages so they are returned as they are and log right away.
+func BeginRequest(l ILog) (ILog, func(emit bool)) {
+	if r, ok := unwrap(l).(requestLogger); ok {
+		return r.beginRequest()
+	}
+
+	return l, func(bool) {}
+}
+
+func (l logger) beginRequest() (ILog, func(emit bool)) {
+	size := l.settings.RequestBufferSize
+	if size <= 0 {
+		size = DefaultRequestBufferSize
+	}
+
+	request := l.withOwnBreadcrumbs()
+	request.request = &requestBuffer{size: size}
+
+	return request, func(emit bool) {
+		messages := request.request.take(true)
+		if !emit {
+			return
+		}
+
+		for _, message := range messages {
+			l.dispatch(message)
+		}
+	}
+}
+
+// flushRequest logs the held messages without ending the request
+func (l logger) flushRequest() {
+	if l.request == nil {
+		return
+	}
+
+	for _, message := range l.request.take(false) {
+		l.dispatch(message)
+	}
+}
@@ -0,0 +1,85 @@
+package log
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBeginRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		log      func(l ILog, request ILog, end func(emit bool))
+		expected []string
+		dropped  uint64
+	}{
+		{
+			name: "emit",
+			log: func(l ILog, request ILog, end func(emit bool)) {
+				request.Print("1")
+				l.Print("direct")
+				request.Print("2")
+				end(true)
+			},
+			expected: []string{"direct", "1", "2"},
+		},
+		{
+			name: "discard",
+			log: func(l ILog, request ILog, end func(emit bool)) {
+				request.Print("1")
+				end(false)
+			},
+			expected: nil,
+		},
+		{
+			name: "after end",
+			log: func(l ILog, request ILog, end func(emit bool)) {
+				request.Print("1")
+				end(false)
+				request.Print("2")
+			},
+			expected: []string{"2"},
+		},
+		{
+			name:     "buffer full drops the oldest",
+			settings: Settings{RequestBufferSize: 2},
+			log: func(l ILog, request ILog, end func(emit bool)) {
+				for _, text := range []string{"1", "2", "3"} {
+					request.Print(text)
+				}
+				end(true)
+			},
+			expected: []string{"2", "3"},
+			dropped:  1,
+		},
+		{
+			name: "fatal logs the held messages first",
+			log: func(l ILog, request ILog, end func(emit bool)) {
+				request.Print("1")
+				func() {
+					defer func() { _ = recover() }()
+					request.Fatal(nil, "fatal")
+				}()
+				request.Print("2")
+				end(false)
+			},
+			expected: []string{"1", "fatal"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l, publisher := newRecordLogger(test.settings)
+			request, end := BeginRequest(l)
+
+			test.log(l, request, end)
+
+			if published := publisher.published(); !reflect.DeepEqual(published, test.expected) {
+				t.Errorf("published %v, expected %v", published, test.expected)
+			}
+			if dropped := GetStats(l).Dropped; dropped != test.dropped {
+				t.Errorf("dropped %d, expected %d", dropped, test.dropped)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package log
+
+import (
+	"sync"
+	"time"
+)
+
+// Defaults used when sampling or rate limiting is turned on
+const (
+	DefaultSampleInterval = time.Second
+	// DefaultSampleReportInterval is how often the dropped message summary is logged while sampling
+	// if Settings.DropReportInterval and Settings.DropReportThreshold are not set
+	DefaultSampleReportInterval = 10 * time.Second
+)
+
+// drop reasons for messages removed by the sampler
+const (
+	dropRateLimited = "rateLimited"
+	dropSampled     = "sampled"
+)
+
+// maxSampledTexts limits how many different messages the sampler tracks in an interval
+const maxSampledTexts = 10000
+
+// SamplingRule sets the sampling of identical messages for one level in place of Settings.SampleFirst
+// and Settings.SampleThereafter
+type SamplingRule struct {
+	Level      Level
+	First      int
+	Thereafter int
+}
+
+// sampler limits the number of messages logged in each interval
+type sampler struct {
+	mutex       sync.Mutex
+	interval    time.Duration
+	limit       int
+	globalLimit int
+	first       int
+	thereafter  int
+	rules       map[int]SamplingRule
+	clock       Clock
+	started     time.Time
+	total       int
+	levels      map[int]int
+	texts       map[sampleKey]int
+}
+
+type sampleKey struct {
+	severity int
+	text     string
+}
+
+func newSampler(settings Settings) *sampler {
+	if settings.RateLimit <= 0 && settings.GlobalRateLimit <= 0 && settings.SampleFirst <= 0 && len(settings.SamplingRules) == 0 {
+		return nil
+	}
+
+	interval := settings.SampleInterval
+	if interval <= 0 {
+		interval = DefaultSampleInterval
+	}
+
+	rules := make(map[int]SamplingRule, len(settings.SamplingRules))
+	for _, rule := range settings.SamplingRules {
+		rules[rule.Level.Severity] = rule
+	}
+
+	return &sampler{
+		interval:    interval,
+		limit:       settings.RateLimit,
+		globalLimit: settings.GlobalRateLimit,
+		first:       settings.SampleFirst,
+		thereafter:  settings.SampleThereafter,
+		rules:       rules,
+		clock:       settingsClock(settings),
+		levels:      map[int]int{},
+		texts:       map[sampleKey]int{},
+	}
+}
+
+// allow checks if the message should be logged, the drop reason is returned if it should not be
+func (s *sampler) allow(level Level, text string) (bool, string) {
+	if s == nil || level.Severity >= FATAL.Severity {
+		return true, ""
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.clock.Now()
+	if now.Sub(s.started) >= s.interval {
+		s.started = now
+		s.total = 0
+		s.levels = map[int]int{}
+		s.texts = map[sampleKey]int{}
+	} else if len(s.texts) >= maxSampledTexts {
+		// only the texts are forgotten so the rate limits still hold for the rest of the interval
+		s.texts = map[sampleKey]int{}
+	}
+
+	first, thereafter := s.first, s.thereafter
+	if rule, ok := s.rules[level.Severity]; ok {
+		first, thereafter = rule.First, rule.Thereafter
+	}
+
+	if first > 0 {
+		key := sampleKey{severity: level.Severity, text: text}
+		count := s.texts[key]
+		s.texts[key] = count + 1
+		if count >= first && (thereafter <= 0 || (count-first+1)%thereafter != 0) {
+			return false, dropSampled
+		}
+	}
+
+	if s.limit > 0 && s.levels[level.Severity] >= s.limit {
+		return false, dropRateLimited
+	}
+
+	if s.globalLimit > 0 && s.total >= s.globalLimit {
+		return false, dropRateLimited
+	}
+
+	s.levels[level.Severity]++
+	s.total++
+
+	return true, ""
+}
+
+// sample checks the message against the sampler recording it as dropped if it should not be logged
+func (l logger) sample(level Level, text string) bool {
+	allowed, reason := l.sampler.allow(level, text)
+	if !allowed {
+		l.dropped(reason)
+	}
+	return allowed
+}
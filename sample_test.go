@@ -0,0 +1,165 @@
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// sampled is a message given to the sampler
+type sampled struct {
+	level Level
+	text  string
+}
+
+func repeat(level Level, text string, count int) []sampled {
+	messages := make([]sampled, count)
+	for i := range messages {
+		messages[i] = sampled{level: level, text: text}
+	}
+	return messages
+}
+
+func TestSampler(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		messages []sampled
+		expected []string
+	}{
+		{
+			name:     "rate limit per level",
+			settings: Settings{RateLimit: 2},
+			messages: append(append(repeat(INFO, "info", 3), repeat(WARNING, "warning", 3)...), repeat(ERROR, "error", 1)...),
+			expected: []string{"", "", dropRateLimited, "", "", dropRateLimited, ""},
+		},
+		{
+			name:     "sample first",
+			settings: Settings{SampleFirst: 2},
+			messages: append(repeat(INFO, "same", 4), sampled{level: INFO, text: "other"}),
+			expected: []string{"", "", dropSampled, dropSampled, ""},
+		},
+		{
+			name:     "sample thereafter",
+			settings: Settings{SampleFirst: 1, SampleThereafter: 2},
+			messages: repeat(INFO, "same", 5),
+			expected: []string{"", dropSampled, "", dropSampled, ""},
+		},
+		{
+			name:     "identical texts of other levels are sampled apart",
+			settings: Settings{SampleFirst: 1},
+			messages: []sampled{{INFO, "same"}, {WARNING, "same"}, {INFO, "same"}},
+			expected: []string{"", "", dropSampled},
+		},
+		{
+			name:     "fatal is never limited",
+			settings: Settings{RateLimit: 1, SampleFirst: 1},
+			messages: repeat(FATAL, "fatal", 3),
+			expected: []string{"", "", ""},
+		},
+		{
+			name:     "sampled messages do not count against the rate limit",
+			settings: Settings{RateLimit: 2, SampleFirst: 1},
+			messages: []sampled{{INFO, "same"}, {INFO, "same"}, {INFO, "other"}, {INFO, "third"}},
+			expected: []string{"", dropSampled, "", dropRateLimited},
+		},
+		{
+			name:     "rules replace the sampling of their level",
+			settings: Settings{SampleFirst: 1, SamplingRules: []SamplingRule{{Level: WARNING, First: 2}}},
+			messages: append(repeat(INFO, "info", 3), repeat(WARNING, "warning", 3)...),
+			expected: []string{"", dropSampled, dropSampled, "", "", dropSampled},
+		},
+		{
+			name:     "rule without sampling",
+			settings: Settings{SampleFirst: 1, SamplingRules: []SamplingRule{{Level: ERROR}}},
+			messages: repeat(ERROR, "error", 3),
+			expected: []string{"", "", ""},
+		},
+		{
+			name:     "global rate limit",
+			settings: Settings{GlobalRateLimit: 2, RateLimit: 5},
+			messages: []sampled{{INFO, "1"}, {WARNING, "2"}, {ERROR, "3"}, {FATAL, "4"}},
+			expected: []string{"", "", dropRateLimited, ""},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			settings := test.settings
+			settings.Clock = newFakeClock()
+			s := newSampler(settings)
+
+			reasons := make([]string, 0, len(test.messages))
+			for _, message := range test.messages {
+				_, reason := s.allow(message.level, message.text)
+				reasons = append(reasons, reason)
+			}
+
+			if !reflect.DeepEqual(reasons, test.expected) {
+				t.Errorf("drop reasons are %q, expected %q", reasons, test.expected)
+			}
+		})
+	}
+}
+
+func TestSamplerInterval(t *testing.T) {
+	clock := newFakeClock()
+	s := newSampler(Settings{RateLimit: 1, SampleFirst: 1, SampleInterval: time.Minute, Clock: clock})
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		allowed bool
+	}{
+		{name: "first", allowed: true},
+		{name: "same interval", advance: 59 * time.Second, allowed: false},
+		{name: "next interval", advance: time.Second, allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clock.Advance(test.advance)
+			if allowed, _ := s.allow(INFO, "same"); allowed != test.allowed {
+				t.Errorf("allowed is %v, expected %v", allowed, test.allowed)
+			}
+		})
+	}
+}
+
+func TestLevelCheckedBeforeSampling(t *testing.T) {
+	m := NewMemoryLogger(Settings{MinLogLevel: WARNING, RateLimit: 1, DropReportThreshold: 100})
+	m.Print("filtered")
+	m.SetLevel(INFO)
+	m.Print("logged")
+
+	if messages := m.Messages(); len(messages) != 1 || messages[0].Text != "logged" {
+		t.Errorf("logged %v, expected only the second message", messages)
+	}
+}
+
+func TestSamplerForgetsTextsNotCounts(t *testing.T) {
+	s := newSampler(Settings{RateLimit: maxSampledTexts + 1, SampleFirst: 1, Clock: newFakeClock()})
+	for i := 0; i < maxSampledTexts; i++ {
+		if allowed, _ := s.allow(INFO, fmt.Sprint(i)); !allowed {
+			t.Fatalf("message %d was not allowed", i)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		text   string
+		reason string
+	}{
+		{name: "texts forgotten", text: "0", reason: ""},
+		{name: "rate limit kept", text: "new", reason: dropRateLimited},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, reason := s.allow(INFO, test.text); reason != test.reason {
+				t.Errorf("drop reason is %q, expected %q", reason, test.reason)
+			}
+		})
+	}
+}
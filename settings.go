@@ -1,6 +1,11 @@
 package log
 
 import (
+	"context"
+	"io"
+	"log"
+	"time"
+
 	"github.com/cjburchell/pubsub"
 	"github.com/cjburchell/uatu-go/publishers"
 )
@@ -14,4 +19,178 @@ type Settings struct {
 	UseHTTP        bool
 	HTTPSettings   publishers.HTTPSettings
 	PubSubSettings pubsub.Settings
+	// UseEventLog sends messages to the windows event log
+	UseEventLog bool
+	// EventLogSettings for the windows event log publisher
+	EventLogSettings publishers.EventLogSettings
+	// UseFile writes messages to a file that is rotated as set in FileSettings
+	UseFile      bool
+	FileSettings publishers.FileSettings
+	// UseSyslog sends messages to syslog in the RFC 5424 format, by default to the local syslog daemon
+	UseSyslog      bool
+	SyslogSettings publishers.SyslogSettings
+	// UseJournald sends messages to systemd-journald with the message fields as journal fields
+	UseJournald      bool
+	JournaldSettings publishers.JournaldSettings
+	// CustomPublishers are sent every message along with the built in publishers
+	CustomPublishers []CustomPublisher
+	// ConsoleMinLogLevel, PubSubMinLogLevel, HTTPMinLogLevel, EventLogMinLogLevel and FileMinLogLevel are the lowest
+	// levels written to the console and sent to each publisher so each can get a different set of messages, e.g. DEBUG
+	// to the console, WARNING to http and ERROR to pub sub. MinLogLevel is checked first so it must be at or below the
+	// lowest of them, a level that is not set gets every message at or above MinLogLevel.
+	ConsoleMinLogLevel  Level
+	PubSubMinLogLevel   Level
+	HTTPMinLogLevel     Level
+	EventLogMinLogLevel Level
+	FileMinLogLevel     Level
+	// SyslogMinLogLevel and JournaldMinLogLevel are the lowest levels sent to syslog and journald
+	SyslogMinLogLevel   Level
+	JournaldMinLogLevel Level
+	// IncludePackage stamps the package of the calling code on each message
+	IncludePackage bool
+	// ComponentLevels are the levels of named loggers, a named logger without a level uses MinLogLevel
+	ComponentLevels map[string]Level
+	// NilFieldValue is how nil field values are rendered, defaults to DefaultNilFieldValue
+	NilFieldValue string
+	// Breadcrumbs is the number of recent messages attached to error level messages, 0 disables them.
+	// Loggers from Named, Child and BeginRequest keep their own breadcrumbs, those from WithField share their parent's.
+	Breadcrumbs int
+	// DropReportInterval is how long after the first dropped message a summary of the dropped messages is logged,
+	// 0 disables the timed summary
+	DropReportInterval time.Duration
+	// DropReportThreshold is the number of dropped messages that triggers a summary right away, 0 disables it
+	DropReportThreshold int
+	// MaxStackLineWidth truncates stack trace lines longer than this, 0 leaves them unchanged
+	MaxStackLineWidth int
+	// Heartbeat logs a service alive message on this interval until the logger is closed, 0 disables it
+	Heartbeat time.Duration
+	// SlowQueryThreshold queries logged with Query that take at least this long are logged as warnings, 0 disables it
+	SlowQueryThreshold time.Duration
+	// LogQueryArgs includes the query arguments in messages logged with Query
+	LogQueryArgs bool
+	// LevelTextCase is the case of the level text sent to the publishers, defaults to LevelTextAsIs
+	LevelTextCase LevelTextCase
+	// DisableStderrFallback stops messages being written to stderr while none of the publishers are working.
+	// By default once every publisher has failed several publishes in a row the messages none of them could
+	// send are written to stderr, even if LogToConsole is off, until one of them works again.
+	DisableStderrFallback bool
+	// MaxHexDumpSize is the maximum number of bytes logged by HexDump, defaults to DefaultMaxHexDumpSize
+	MaxHexDumpSize int
+	// PauseBufferSize is the number of messages held while publishing is paused, once full OverflowPolicy applies.
+	// Defaults to DefaultPauseBufferSize
+	PauseBufferSize int
+	// Enricher is called with each message just before it is printed and published so it can change the message
+	// or add fields. It runs on the logging path of the caller so it must be fast and must not block.
+	Enricher func(message *Message)
+	// Hooks are run in order on each message before it is printed and published. A message is checked against
+	// MinLogLevel, then the sampler and rate limits, then the Enricher is run and then the hooks, so hooks only see
+	// messages that made it past the level and sampling. The console and publisher levels are checked after the hooks.
+	// A hook can change the message or return false to drop it.
+	Hooks []Hook
+	// RateLimit is the most messages of each level logged in each SampleInterval, 0 does not limit them
+	RateLimit int
+	// SampleFirst is the number of identical messages of each level logged in each SampleInterval before only
+	// one in every SampleThereafter is logged, 0 turns sampling off. Fatal messages are never sampled or limited.
+	SampleFirst      int
+	SampleThereafter int
+	// SamplingRules set the sampling of some levels in place of SampleFirst and SampleThereafter
+	SamplingRules []SamplingRule
+	// GlobalRateLimit is the most messages of all levels logged in each SampleInterval, 0 does not limit them
+	GlobalRateLimit int
+	// SampleInterval defaults to DefaultSampleInterval
+	SampleInterval time.Duration
+	// FingerprintErrors sets FingerprintField on messages logged with an error to a fingerprint of the error's type
+	// and the top frame of its stack trace
+	FingerprintErrors bool
+	// DedupeWindow logs only the first of the errors with the same fingerprint and level in this window, the repeats
+	// are logged as one message with RepeatField once the window ends. Fatal errors are never suppressed, 0 turns it off.
+	DedupeWindow time.Duration
+	// OnPublishError is called with each message a publisher fails to publish after any retries,
+	// when it is not set the failure is printed by the InternalLogger
+	OnPublishError func(err error, message Message)
+	// PublishRetries is the number of times a failed publish is retried, publishers that retry on their own such as
+	// http with HTTPSettings.MaxAttempts above 1 are not retried again
+	PublishRetries int
+	// RetryBackoff is the wait before the first retry, it doubles for each retry up to RetryMaxBackoff
+	RetryBackoff    time.Duration
+	RetryMaxBackoff time.Duration
+	// SpoolPath is a directory messages that could not be published are kept in until the publisher recovers,
+	// empty turns spooling off
+	SpoolPath string
+	// SpoolMaxSize is the most bytes spooled for each publisher, 0 does not limit it
+	SpoolMaxSize int64
+	// RetryBudgetSize is the number of retries all the publishers can make at once, 0 does not limit the retries
+	RetryBudgetSize int
+	// RetryBudgetRefill is the number of retries added back to the budget each second
+	RetryBudgetRefill float64
+	// IDGenerator generates the ID of each message, defaults to NewUUID. It is called from every goroutine that logs
+	// so it must be safe for concurrent use.
+	IDGenerator func() string
+	// FieldNamespace nests the fields sent to the publishers under this key, by default the fields are not nested
+	FieldNamespace string
+	// PubSubEnabled when set is checked for every message and the pub sub publisher is skipped while it returns false
+	PubSubEnabled func() bool
+	// HTTPEnabled when set is checked for every message and the http publisher is skipped while it returns false
+	HTTPEnabled func() bool
+	// InternalLogger receives the logger's own diagnostics such as publisher failures, defaults to stderr.
+	// Use log.New(ioutil.Discard, "", 0) to silence them.
+	InternalLogger *log.Logger
+	// AsyncConsole writes the console from a background goroutine so logging does not wait on a slow stdout,
+	// the buffered lines are written on Close
+	AsyncConsole bool
+	// AsyncConsoleBufferSize is the number of lines buffered by the async console, once full lines are dropped.
+	// Defaults to DefaultAsyncConsoleBufferSize
+	AsyncConsoleBufferSize int
+	// MaxObjectDepth is how deep the objects logged with InfoObj are converted to fields, defaults to DefaultMaxObjectDepth
+	MaxObjectDepth int
+	// MaxObjectFields is the number of fields or elements converted at each level of an object logged with InfoObj,
+	// defaults to DefaultMaxObjectFields
+	MaxObjectFields int
+	// PublishTimeout logs a warning through the InternalLogger when a single publish takes longer than this, 0 disables it
+	PublishTimeout time.Duration
+	// BlockedPublisherCooldown skips a publisher for this long after it took longer than PublishTimeout
+	BlockedPublisherCooldown time.Duration
+	// ConsoleFormat is how messages are written to the console, defaults to TextFormat
+	ConsoleFormat ConsoleFormat
+	// ConsoleFormatter formats the messages written to the console in place of ConsoleFormat
+	ConsoleFormatter func(message Message) string
+	// ConsoleWriter is where console output is written, defaults to os.Stdout
+	ConsoleWriter io.Writer
+	// RequestBufferSize is the number of messages a logger from BeginRequest holds, once full the oldest are dropped.
+	// Defaults to DefaultRequestBufferSize
+	RequestBufferSize int
+	// TimestampWindow is how far from now the time of a message given to LogBatch can be, 0 accepts any time
+	TimestampWindow time.Duration
+	// TimestampPolicy is what happens to messages outside of the TimestampWindow, defaults to ClampTimestamps
+	TimestampPolicy TimestampPolicy
+	// Async publishes messages from a background goroutine so logging does not wait on the publishers,
+	// use Flush or Close to wait for the buffered messages to be published
+	Async bool
+	// BufferSize is the number of messages buffered in async mode counting each message of a batch,
+	// defaults to DefaultBufferSize
+	BufferSize int
+	// OverflowPolicy is what happens when the async buffer or the pause buffer is full, defaults to DropOldest
+	OverflowPolicy OverflowPolicy
+	// AsyncWorkers is the number of goroutines publishing in async mode, defaults to 1.
+	// With more than one the messages may reach the publishers out of order.
+	AsyncWorkers int
+	// BatchSize collects messages and publishes them together once this many are collected or BatchInterval
+	// has passed, publishers that implement publishers.BatchPublisher get them in one publish. 0 turns batching off.
+	BatchSize int
+	// BatchInterval defaults to DefaultBatchInterval
+	BatchInterval time.Duration
+	// IncludeCaller sets the file, line and function of the code that logged each message, finding them adds overhead
+	IncludeCaller bool
+	// CallerSkip is the number of frames skipped past the code that called the logger, for helpers that wrap it
+	CallerSkip int
+	// ContextKeys are the context values WithContext attaches to messages
+	ContextKeys []ContextKey
+	// TraceExtractor gets the trace and span ids from the context for WithContext, e.g. from an OpenTelemetry span
+	TraceExtractor func(ctx context.Context) (traceID string, spanID string)
+	// Clock provides the time messages are stamped with and the time used for sampling, dedupe windows,
+	// drop reports, heartbeat uptime and file rotation, defaults to the system time. If it is a TimerClock
+	// the dedupe and drop report timers run on it too.
+	Clock Clock
+	// TimeFormat is the layout the time is printed with in the text console format, defaults to DefaultTimeFormat
+	TimeFormat string
 }
@@ -0,0 +1,113 @@
+package settings
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cjburchell/settings-go"
+	log "github.com/cjburchell/uatu-go"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// LoadFromEnv loads the log settings from environment variables. The variables have the same names as the
+// keys read by Get and sections are joined with an underscore e.g. ServiceName, MinLogLevel, Http_Enabled,
+// Http_Endpoint, Http_Token, PubSub_Enabled and File_Path.
+func LoadFromEnv() log.Settings {
+	return Get(settings.Get(""))
+}
+
+// LoadFromFile loads the log settings from a .json or .yaml file, environment variables are used for the
+// keys that are not in the file. An error is returned if the file can not be parsed.
+func LoadFromFile(path string) (log.Settings, error) {
+	if err := checkFile(path); err != nil {
+		return log.Settings{}, err
+	}
+
+	if err := parseFile(path); err != nil {
+		return log.Settings{}, err
+	}
+
+	return Get(settings.Get(path)), nil
+}
+
+func checkFile(path string) error {
+	// settings-go only reads files with these exact extensions
+	switch filepath.Ext(path) {
+	case ".json", ".yaml":
+	default:
+		return errors.Errorf("unsupported settings file %s, only .json and .yaml files are supported", path)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return errors.Wrap(err, "unable to read settings file")
+	}
+
+	return nil
+}
+
+// parseFile checks that the file parses, settings-go ignores files it can not parse and uses the defaults
+func parseFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "unable to read settings file")
+	}
+
+	var values map[string]interface{}
+	if filepath.Ext(path) == ".json" {
+		err = json.Unmarshal(data, &values)
+	} else {
+		err = yaml.Unmarshal(data, &values)
+	}
+
+	return errors.Wrapf(err, "unable to parse settings file %s", path)
+}
+
+// Watch checks the settings file on every interval and applies a changed MinLogLevel to the logger,
+// the other settings need the logger to be created again. A file that can not be parsed, e.g. while it is
+// being written, is skipped and checked again on the next interval. Call the returned function to stop watching,
+// it is safe to call more than once.
+func Watch(path string, logger log.ILog, interval time.Duration) (stop func(), err error) {
+	if err = checkFile(path); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		modified := info.ModTime()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				info, err := os.Stat(path)
+				if err != nil || !info.ModTime().After(modified) {
+					continue
+				}
+
+				loaded, err := LoadFromFile(path)
+				if err != nil {
+					continue
+				}
+
+				modified = info.ModTime()
+				logger.SetLevel(loaded.MinLogLevel)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }, nil
+}
@@ -0,0 +1,70 @@
+package settings
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	log "github.com/cjburchell/uatu-go"
+)
+
+func writeSettingsFile(t *testing.T, name string, content string) string {
+	dir, err := ioutil.TempDir("", "settings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFromFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		fails    bool
+		expected log.Level
+	}{
+		{name: "json", file: "log.json", content: `{"MinLogLevel": "Error"}`, expected: log.ERROR},
+		{name: "yaml", file: "log.yaml", content: "MinLogLevel: Warning\n", expected: log.WARNING},
+		{name: "invalid json", file: "log.json", content: `{"MinLogLevel": `, fails: true},
+		{name: "invalid yaml", file: "log.yaml", content: "MinLogLevel: [Warning\n", fails: true},
+		{name: "unsupported extension", file: "log.toml", content: `MinLogLevel = "Error"`, fails: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			loaded, err := LoadFromFile(writeSettingsFile(t, test.file, test.content))
+			if test.fails {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if loaded.MinLogLevel != test.expected {
+				t.Errorf("MinLogLevel is %s, expected %s", loaded.MinLogLevel.Text, test.expected.Text)
+			}
+		})
+	}
+}
+
+func TestWatchStopTwice(t *testing.T) {
+	stop, err := Watch(writeSettingsFile(t, "log.json", `{}`), log.NewNopLogger(), time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	stop()
+	stop()
+}
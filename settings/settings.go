@@ -1,6 +1,8 @@
 package settings
 
 import (
+	"time"
+
 	pubSubSettings "github.com/cjburchell/pubsub/settings"
 	"github.com/cjburchell/settings-go"
 	log "github.com/cjburchell/uatu-go"
@@ -10,19 +12,139 @@ import (
 // Get the log settings
 func Get(settings settings.ISettings) log.Settings {
 	return log.Settings{
-		ServiceName:    settings.Get("ServiceName", ""),
-		MinLogLevel:    log.GetLogLevel(settings.Get("MinLogLevel", log.INFO.Text)),
-		LogToConsole:   settings.GetBool("LogToConsole", true),
-		HTTPSettings:   createHTTPSettings(settings.GetSection("Http")),
-		PubSubSettings: pubSubSettings.Get(settings.GetSection("PubSub")),
-		UseHTTP:        settings.GetSection("Http").GetBool("Enabled", false),
-		UsePubSub:      settings.GetSection("PubSub").GetBool("Enabled", false),
+		ServiceName:              settings.Get("ServiceName", ""),
+		MinLogLevel:              log.GetLogLevel(settings.Get("MinLogLevel", log.INFO.Text)),
+		LogToConsole:             settings.GetBool("LogToConsole", true),
+		HTTPSettings:             createHTTPSettings(settings.GetSection("Http")),
+		PubSubSettings:           pubSubSettings.Get(settings.GetSection("PubSub")),
+		UseHTTP:                  settings.GetSection("Http").GetBool("Enabled", false),
+		UsePubSub:                settings.GetSection("PubSub").GetBool("Enabled", false),
+		UseEventLog:              settings.GetSection("EventLog").GetBool("Enabled", false),
+		EventLogSettings:         createEventLogSettings(settings.GetSection("EventLog")),
+		UseFile:                  settings.GetSection("File").GetBool("Enabled", false),
+		FileSettings:             createFileSettings(settings.GetSection("File")),
+		ConsoleMinLogLevel:       log.GetLogLevel(settings.Get("ConsoleMinLogLevel", log.TRACE.Text)),
+		FileMinLogLevel:          log.GetLogLevel(settings.GetSection("File").Get("MinLogLevel", log.TRACE.Text)),
+		PubSubMinLogLevel:        log.GetLogLevel(settings.GetSection("PubSub").Get("MinLogLevel", log.TRACE.Text)),
+		HTTPMinLogLevel:          log.GetLogLevel(settings.GetSection("Http").Get("MinLogLevel", log.TRACE.Text)),
+		EventLogMinLogLevel:      log.GetLogLevel(settings.GetSection("EventLog").Get("MinLogLevel", log.TRACE.Text)),
+		UseSyslog:                settings.GetSection("Syslog").GetBool("Enabled", false),
+		SyslogSettings:           createSyslogSettings(settings.GetSection("Syslog")),
+		SyslogMinLogLevel:        log.GetLogLevel(settings.GetSection("Syslog").Get("MinLogLevel", log.TRACE.Text)),
+		UseJournald:              settings.GetSection("Journald").GetBool("Enabled", false),
+		JournaldSettings:         createJournaldSettings(settings.GetSection("Journald")),
+		JournaldMinLogLevel:      log.GetLogLevel(settings.GetSection("Journald").Get("MinLogLevel", log.TRACE.Text)),
+		IncludePackage:           settings.GetBool("IncludePackage", false),
+		Breadcrumbs:              settings.GetInt("Breadcrumbs", 0),
+		MaxStackLineWidth:        settings.GetInt("MaxStackLineWidth", 0),
+		DropReportInterval:       getDuration(settings, "DropReportInterval", 0),
+		DropReportThreshold:      settings.GetInt("DropReportThreshold", 0),
+		Heartbeat:                getDuration(settings, "Heartbeat", 0),
+		SlowQueryThreshold:       getDuration(settings, "SlowQueryThreshold", 0),
+		LogQueryArgs:             settings.GetBool("LogQueryArgs", false),
+		LevelTextCase:            log.LevelTextCase(settings.Get("LevelTextCase", string(log.LevelTextAsIs))),
+		DisableStderrFallback:    settings.GetBool("DisableStderrFallback", false),
+		MaxHexDumpSize:           settings.GetInt("MaxHexDumpSize", log.DefaultMaxHexDumpSize),
+		PublishRetries:           settings.GetInt("PublishRetries", 0),
+		RetryBudgetSize:          settings.GetInt("RetryBudgetSize", 0),
+		RetryBackoff:             getDuration(settings, "RetryBackoff", 0),
+		RetryMaxBackoff:          getDuration(settings, "RetryMaxBackoff", 0),
+		SpoolPath:                settings.Get("SpoolPath", ""),
+		SpoolMaxSize:             settings.GetInt64("SpoolMaxSize", 0),
+		RetryBudgetRefill:        float64(settings.GetInt("RetryBudgetRefill", 0)),
+		FieldNamespace:           settings.Get("FieldNamespace", ""),
+		AsyncConsole:             settings.GetBool("AsyncConsole", false),
+		PublishTimeout:           getDuration(settings, "PublishTimeout", 0),
+		BlockedPublisherCooldown: getDuration(settings, "BlockedPublisherCooldown", 0),
+		ConsoleFormat:            log.ConsoleFormat(settings.Get("ConsoleFormat", string(log.TextFormat))),
+		TimestampWindow:          getDuration(settings, "TimestampWindow", 0),
+		TimestampPolicy:          log.TimestampPolicy(settings.Get("TimestampPolicy", string(log.ClampTimestamps))),
+		Async:                    settings.GetBool("Async", false),
+		BufferSize:               settings.GetInt("BufferSize", log.DefaultBufferSize),
+		OverflowPolicy:           log.OverflowPolicy(settings.Get("OverflowPolicy", string(log.DropOldest))),
+		AsyncWorkers:             settings.GetInt("AsyncWorkers", 1),
+		BatchSize:                settings.GetInt("BatchSize", 0),
+		BatchInterval:            getDuration(settings, "BatchInterval", log.DefaultBatchInterval),
+		IncludeCaller:            settings.GetBool("IncludeCaller", false),
+		CallerSkip:               settings.GetInt("CallerSkip", 0),
+		RateLimit:                settings.GetInt("RateLimit", 0),
+		GlobalRateLimit:          settings.GetInt("GlobalRateLimit", 0),
+		SampleFirst:              settings.GetInt("SampleFirst", 0),
+		SampleThereafter:         settings.GetInt("SampleThereafter", 0),
+		SampleInterval:           getDuration(settings, "SampleInterval", log.DefaultSampleInterval),
+		TimeFormat:               settings.Get("TimeFormat", log.DefaultTimeFormat),
+		FingerprintErrors:        settings.GetBool("FingerprintErrors", false),
+		DedupeWindow:             getDuration(settings, "DedupeWindow", 0),
+	}
+}
+
+// getDuration reads a duration such as "1m30s", the fallback is used if it is missing or invalid
+func getDuration(settings settings.ISettings, key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(settings.Get(key, fallback.String()))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getHeaders(settings settings.ISettings) map[string]string {
+	var headers map[string]string
+	if err := settings.GetObject("Headers", &headers); err != nil {
+		return nil
 	}
+	return headers
 }
 
 func createHTTPSettings(settings settings.ISettings) publishers.HTTPSettings {
 	return publishers.HTTPSettings{
-		Address: settings.Get("Endpoint", "http://logger:8082/log"),
-		Token:   settings.Get("Token", "token"),
+		Address:      settings.Get("Endpoint", "http://logger:8082/log"),
+		Token:        settings.Get("Token", "token"),
+		MaxAttempts:  settings.GetInt("MaxAttempts", 1),
+		BaseDelay:    getDuration(settings, "BaseDelay", publishers.DefaultHTTPBaseDelay),
+		MaxDelay:     getDuration(settings, "MaxDelay", publishers.DefaultHTTPMaxDelay),
+		Timeout:      getDuration(settings, "Timeout", publishers.DefaultHTTPTimeout),
+		BatchAddress: settings.Get("BatchEndpoint", ""),
+		BearerToken:  settings.Get("BearerToken", ""),
+		Username:     settings.Get("Username", ""),
+		Password:     settings.Get("Password", ""),
+		CertFile:     settings.Get("CertFile", ""),
+		KeyFile:      settings.Get("KeyFile", ""),
+		CAFile:       settings.Get("CAFile", ""),
+		Headers:      getHeaders(settings),
+	}
+}
+
+func createFileSettings(settings settings.ISettings) publishers.FileSettings {
+	return publishers.FileSettings{
+		Path:       settings.Get("Path", "logs/service.log"),
+		MaxSize:    settings.GetInt64("MaxSize", 0),
+		MaxAge:     getDuration(settings, "MaxAge", 0),
+		MaxBackups: settings.GetInt("MaxBackups", 0),
+		Compress:   settings.GetBool("Compress", false),
+	}
+}
+
+func createSyslogSettings(settings settings.ISettings) publishers.SyslogSettings {
+	return publishers.SyslogSettings{
+		Network:          settings.Get("Network", ""),
+		Address:          settings.Get("Address", ""),
+		AppName:          settings.Get("AppName", ""),
+		Facility:         settings.GetInt("Facility", publishers.SyslogFacilityUser),
+		StructuredDataID: settings.Get("StructuredDataId", publishers.DefaultSyslogStructuredDataID),
+	}
+}
+
+func createJournaldSettings(settings settings.ISettings) publishers.JournaldSettings {
+	return publishers.JournaldSettings{
+		Socket:     settings.Get("Socket", publishers.DefaultJournaldSocket),
+		Identifier: settings.Get("Identifier", ""),
+	}
+}
+
+func createEventLogSettings(settings settings.ISettings) publishers.EventLogSettings {
+	return publishers.EventLogSettings{
+		Source:  settings.Get("Source", "uatu"),
+		EventID: uint32(settings.GetInt("EventId", 1)),
+		Install: settings.GetBool("Install", false),
 	}
 }
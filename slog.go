@@ -0,0 +1,124 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// slogHandler sends slog records to the logger
+type slogHandler struct {
+	log    ILog
+	fields map[string]interface{}
+	group  string
+}
+
+// NewSlogHandler creates a slog.Handler that logs the records with the logger, attributes become fields
+// and the names of groups are added before the attribute names with a dot
+func NewSlogHandler(l ILog) slog.Handler {
+	return slogHandler{log: l}
+}
+
+// SlogLevel converts the slog level to the matching level
+func SlogLevel(level slog.Level) Level {
+	switch {
+	case level >= slog.LevelError:
+		return ERROR
+	case level >= slog.LevelWarn:
+		return WARNING
+	case level >= slog.LevelInfo:
+		return INFO
+	case level >= slog.LevelDebug:
+		return DEBUG
+	default:
+		return TRACE
+	}
+}
+
+func (h slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return enabled(h.log, SlogLevel(level))
+}
+
+func (h slogHandler) Handle(_ context.Context, record slog.Record) error {
+	fields := make(map[string]interface{}, len(h.fields)+record.NumAttrs())
+	for key, value := range h.fields {
+		fields[key] = value
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		addSlogAttr(fields, h.group, attr)
+		return true
+	})
+
+	l := h.log
+	if len(fields) != 0 {
+		l = l.WithFields(fields)
+	}
+
+	switch level := SlogLevel(record.Level); level {
+	case ERROR:
+		l.Error(nil, record.Message)
+	case WARNING:
+		l.Warn(record.Message)
+	case INFO:
+		l.Print(record.Message)
+	case DEBUG:
+		l.Debug(record.Message)
+	default:
+		l.Trace(record.Message)
+	}
+
+	return nil
+}
+
+func (h slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make(map[string]interface{}, len(h.fields)+len(attrs))
+	for key, value := range h.fields {
+		fields[key] = value
+	}
+	for _, attr := range attrs {
+		addSlogAttr(fields, h.group, attr)
+	}
+
+	h.fields = fields
+	return h
+}
+
+func (h slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h.group = slogKey(h.group, name)
+	return h
+}
+
+func slogKey(group string, key string) string {
+	if group == "" {
+		return key
+	}
+	return fmt.Sprintf("%s.%s", group, key)
+}
+
+// addSlogAttr adds the attribute to the fields flattening groups into dotted names
+func addSlogAttr(fields map[string]interface{}, group string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if value.Kind() == slog.KindGroup {
+		// a group without a key is added inline
+		if attr.Key != "" {
+			group = slogKey(group, attr.Key)
+		}
+		for _, child := range value.Group() {
+			addSlogAttr(fields, group, child)
+		}
+		return
+	}
+
+	fields[slogKey(group, attr.Key)] = value.Any()
+}
@@ -0,0 +1,183 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// spool keeps the messages a publisher failed to publish on disk until it recovers
+type spool struct {
+	mutex     sync.Mutex
+	path      string
+	maxSize   int64
+	size      int64
+	replaying bool
+}
+
+func newSpool(dir string, name string, maxSize int64) (*spool, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	s := &spool{path: filepath.Join(dir, name+".spool"), maxSize: maxSize}
+	if info, err := os.Stat(s.path); err == nil {
+		s.size = info.Size()
+	}
+	return s, nil
+}
+
+// write adds the message to the spool, false is returned if it did not fit
+func (s *spool) write(data []byte) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	line := append(append(make([]byte, 0, len(data)+1), data...), '\n')
+	if s.maxSize > 0 && s.size+int64(len(line)) > s.maxSize {
+		return false, nil
+	}
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return false, err
+	}
+
+	written, err := file.Write(line)
+	s.size += int64(written)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err == nil, err
+}
+
+// take the spooled messages oldest first and empty the spool, nothing is returned while another
+// goroutine is replaying. restore must be called once the messages have been replayed.
+func (s *spool) take() ([][]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.size == 0 || s.replaying {
+		return nil, nil
+	}
+
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.Remove(s.path); err != nil {
+		return nil, err
+	}
+
+	s.size = 0
+	s.replaying = true
+	return splitLines(data), nil
+}
+
+// restore puts the messages that could not be replayed back in the spool ahead of the ones spooled since
+// they were taken, the spool can then be replayed again
+func (s *spool) restore(lines [][]byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.replaying = false
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	var data bytes.Buffer
+	for _, line := range lines {
+		data.Write(line)
+		data.WriteByte('\n')
+	}
+
+	spooled, err := ioutil.ReadFile(s.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	data.Write(spooled)
+
+	s.size = int64(data.Len())
+	return ioutil.WriteFile(s.path, data.Bytes(), 0644)
+}
+
+// busy is true if there are spooled messages or they are being replayed
+func (s *spool) busy() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.size != 0 || s.replaying
+}
+
+func splitLines(data []byte) [][]byte {
+	var lines [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		if line := scanner.Bytes(); len(line) != 0 {
+			lines = append(lines, append([]byte(nil), line...))
+		}
+	}
+	return lines
+}
+
+// spoolFailed writes the message to the sink's spool, true is returned if it was spooled
+func (l logger) spoolFailed(s *sink, data []byte) bool {
+	if s.spool == nil || data == nil {
+		return false
+	}
+
+	spooled, err := s.spool.write(data)
+	if err != nil {
+		l.internal().Printf("Unable to spool log for publisher %s (%s)", s.name, err.Error())
+	}
+
+	return spooled
+}
+
+// replaySpool publishes the sink's spooled messages oldest first so they go out before newer messages,
+// false is returned if messages are still spooled because the publisher failed again or another goroutine is
+// replaying them. New messages should then be spooled too so they are not published out of order.
+func (l logger) replaySpool(s *sink) bool {
+	if s.spool == nil {
+		return true
+	}
+
+	for {
+		lines, err := s.spool.take()
+		if err != nil {
+			l.internal().Printf("Unable to replay spooled logs for publisher %s (%s)", s.name, err.Error())
+			return false
+		}
+
+		if lines == nil {
+			return !s.spool.busy()
+		}
+
+		for i, line := range lines {
+			if err = l.watch(s, func() error { return s.publisher.Publish(line) }); err != nil {
+				if err != errAbandoned {
+					l.publishFailed(s, 1)
+				}
+				lines = lines[i:]
+				break
+			}
+			l.publishSucceeded(s, 1)
+		}
+
+		if err == nil {
+			lines = nil
+		}
+
+		if restoreErr := s.spool.restore(lines); restoreErr != nil {
+			l.internal().Printf("Unable to spool logs for publisher %s (%s)", s.name, restoreErr.Error())
+		}
+
+		if err != nil {
+			return false
+		}
+	}
+}
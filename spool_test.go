@@ -0,0 +1,99 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// flakyPublisher fails to publish each text the number of times it is given
+type flakyPublisher struct {
+	mutex    sync.Mutex
+	failures map[string]int
+	texts    []string
+}
+
+func (p *flakyPublisher) Publish(messageBites []byte) error {
+	var message Message
+	if err := json.Unmarshal(messageBites, &message); err != nil {
+		return err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.failures[message.Text] > 0 {
+		p.failures[message.Text]--
+		return errors.New("publisher is down")
+	}
+
+	p.texts = append(p.texts, message.Text)
+	return nil
+}
+
+func TestSpoolReplaysInOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  map[string]int
+		texts     []string
+		published []string
+		failed    uint64
+	}{
+		{
+			name:      "recovers",
+			failures:  map[string]int{"1": 1, "2": 1},
+			texts:     []string{"1", "2", "3"},
+			published: []string{"1", "2", "3"},
+			failed:    2,
+		},
+		{
+			name:      "fails again while replaying",
+			failures:  map[string]int{"1": 1, "2": 2},
+			texts:     []string{"1", "2", "3", "4"},
+			published: []string{"1", "2", "3", "4"},
+			failed:    3,
+		},
+		{
+			name:      "still down",
+			failures:  map[string]int{"1": 5},
+			texts:     []string{"1", "2", "3"},
+			published: nil,
+			failed:    3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "spool")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			publisher := &flakyPublisher{failures: test.failures}
+			l := Create(Settings{
+				MinLogLevel:           INFO,
+				SpoolPath:             dir,
+				DisableStderrFallback: true,
+				OnPublishError:        func(error, Message) {},
+				CustomPublishers:      []CustomPublisher{{Name: "flaky", Publisher: publisher}},
+			})
+
+			for _, text := range test.texts {
+				l.Print(text)
+			}
+
+			if !reflect.DeepEqual(publisher.texts, test.published) {
+				t.Errorf("published %v, expected %v", publisher.texts, test.published)
+			}
+
+			stats := GetStats(l).Publishers["flaky"]
+			if stats.Failed != test.failed || stats.Published != uint64(len(test.published)) {
+				t.Errorf("counted %d published and %d failed, expected %d and %d", stats.Published, stats.Failed, len(test.published), test.failed)
+			}
+		})
+	}
+}
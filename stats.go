@@ -0,0 +1,111 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Stats are counts of what the logger has done since it was created
+type Stats struct {
+	// Levels is the number of messages logged at each level
+	Levels map[string]uint64
+	// Dropped is the number of messages dropped by limits, sampling and full buffers
+	Dropped uint64
+	// QueueDepth is the number of messages waiting to be published in async mode
+	QueueDepth int
+	// Publishers are the counts for each publisher by name
+	Publishers map[string]PublisherStats
+}
+
+// PublisherStats are the counts for one publisher
+type PublisherStats struct {
+	Published uint64
+	Failed    uint64
+	// Stalls is the number of publishes that took longer than Settings.PublishTimeout
+	Stalls uint64
+}
+
+// stats are the counters shared by a logger and its children
+type stats struct {
+	// dropped is first so it is aligned for atomic access
+	dropped uint64
+	mutex   sync.Mutex
+	levels  map[string]uint64
+}
+
+func newStats() *stats {
+	return &stats{levels: map[string]uint64{}}
+}
+
+func (s *stats) logged(level Level) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.levels[level.Text]++
+}
+
+// statsLogger is implemented by the loggers in this package that keep counts
+type statsLogger interface {
+	getStats() Stats
+}
+
+// GetStats gets the logger's counts, loggers from other packages have no counts
+func GetStats(l ILog) Stats {
+	if s, ok := unwrap(l).(statsLogger); ok {
+		return s.getStats()
+	}
+
+	return Stats{Levels: map[string]uint64{}, Publishers: map[string]PublisherStats{}}
+}
+
+func (l logger) getStats() Stats {
+	result := Stats{
+		Levels:     map[string]uint64{},
+		Dropped:    atomic.LoadUint64(&l.stats.dropped),
+		Publishers: make(map[string]PublisherStats, len(l.sinks)),
+	}
+
+	l.stats.mutex.Lock()
+	for level, count := range l.stats.levels {
+		result.Levels[level] = count
+	}
+	l.stats.mutex.Unlock()
+
+	if l.queue != nil {
+		result.QueueDepth = l.queue.depth()
+	}
+
+	for _, s := range l.sinks {
+		result.Publishers[s.name] = PublisherStats{
+			Published: atomic.LoadUint64(&s.published),
+			Failed:    atomic.LoadUint64(&s.failed),
+			Stalls:    atomic.LoadUint64(&s.stalls),
+		}
+	}
+
+	return result
+}
+
+// add the counts of the other stats to these ones
+func (s *Stats) add(other Stats) {
+	if s.Levels == nil {
+		s.Levels = map[string]uint64{}
+	}
+	if s.Publishers == nil {
+		s.Publishers = map[string]PublisherStats{}
+	}
+
+	for level, count := range other.Levels {
+		s.Levels[level] += count
+	}
+
+	s.Dropped += other.Dropped
+	s.QueueDepth += other.QueueDepth
+
+	for name, publisher := range other.Publishers {
+		total := s.Publishers[name]
+		total.Published += publisher.Published
+		total.Failed += publisher.Failed
+		total.Stalls += publisher.Stalls
+		s.Publishers[name] = total
+	}
+}
@@ -0,0 +1,66 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"sync"
+)
+
+// StdLogger creates a standard library logger that logs each line it is given at the level
+func StdLogger(l ILog, level Level) *log.Logger {
+	return log.New(NewLineWriter(l, level), "", 0)
+}
+
+// RedirectStdLog sends the output of the standard library's global logger to the logger at the level,
+// the returned function puts the global logger back the way it was
+func RedirectStdLog(l ILog, level Level) func() {
+	flags, prefix, writer := log.Flags(), log.Prefix(), log.Writer()
+
+	// the logger stamps its own time
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(NewLineWriter(l, level))
+
+	return func() {
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+		log.SetOutput(writer)
+	}
+}
+
+// LineWriter logs each complete line written to it, a partial line is held until the rest of it is written or Flush is called
+type LineWriter struct {
+	mutex  sync.Mutex
+	writer io.Writer
+	buffer bytes.Buffer
+}
+
+// NewLineWriter creates a LineWriter that logs at the level
+func NewLineWriter(l ILog, level Level) *LineWriter {
+	return &LineWriter{writer: l.GetWriter(level)}
+}
+
+func (w *LineWriter) Write(p []byte) (n int, err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.buffer.Write(p)
+	if end := bytes.LastIndexByte(w.buffer.Bytes(), '\n'); end >= 0 {
+		lines := w.buffer.Next(end + 1)
+		_, _ = w.writer.Write(lines)
+	}
+
+	return len(p), nil
+}
+
+// Flush logs the partial line that is being held
+func (w *LineWriter) Flush() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.buffer.Len() != 0 {
+		_, _ = w.writer.Write(w.buffer.Bytes())
+		w.buffer.Reset()
+	}
+}
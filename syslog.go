@@ -0,0 +1,67 @@
+package log
+
+// Syslog severities
+const (
+	SyslogEmergency = 0
+	SyslogAlert     = 1
+	SyslogCritical  = 2
+	SyslogError     = 3
+	SyslogWarning   = 4
+	SyslogNotice    = 5
+	SyslogInfo      = 6
+	SyslogDebug     = 7
+)
+
+// LevelFromSyslog maps a syslog severity to a level
+//
+//	0 Emergency, 1 Alert, 2 Critical -> FATAL
+//	3 Error                          -> ERROR
+//	4 Warning                        -> WARNING
+//	5 Notice, 6 Informational        -> INFO
+//	7 Debug                          -> DEBUG
+//
+// Severities below 0 are treated as 0 and above 7 as 7.
+func LevelFromSyslog(pri int) Level {
+	switch {
+	case pri <= SyslogCritical:
+		return FATAL
+	case pri == SyslogError:
+		return ERROR
+	case pri == SyslogWarning:
+		return WARNING
+	case pri <= SyslogInfo:
+		return INFO
+	default:
+		return DEBUG
+	}
+}
+
+// ToSyslog maps the level to a syslog severity by its Severity
+//
+//	FATAL   -> 2 Critical
+//	ERROR   -> 3 Error
+//	WARNING -> 4 Warning
+//	INFO    -> 6 Informational
+//	DEBUG   -> 7 Debug
+//
+// Levels between these map to the syslog severity of the closest level below them.
+func (level Level) ToSyslog() int {
+	switch {
+	case level.Severity >= FATAL.Severity:
+		return SyslogCritical
+	case level.Severity >= ERROR.Severity:
+		return SyslogError
+	case level.Severity >= WARNING.Severity:
+		return SyslogWarning
+	case level.Severity >= INFO.Severity:
+		return SyslogInfo
+	default:
+		return SyslogDebug
+	}
+}
+
+// LogSyslog logs the text at the level mapped from the syslog severity by LevelFromSyslog.
+// Unlike Fatal it does not panic for the emergency, alert and critical severities.
+func LogSyslog(l ILog, pri int, text string) {
+	l.Log(LevelFromSyslog(pri), text)
+}
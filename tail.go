@@ -0,0 +1,214 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// tail keeps the last messages logged and streams new messages to its listeners
+type tail struct {
+	mutex     sync.Mutex
+	messages  []Message
+	next      int
+	full      bool
+	listeners map[chan Message]struct{}
+}
+
+func newTail(size int) *tail {
+	if size <= 0 {
+		size = 1
+	}
+
+	return &tail{
+		messages:  make([]Message, size),
+		listeners: map[chan Message]struct{}{},
+	}
+}
+
+func (t *tail) add(message Message) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.messages[t.next] = message
+	t.next = (t.next + 1) % len(t.messages)
+	if t.next == 0 {
+		t.full = true
+	}
+
+	for listener := range t.listeners {
+		select {
+		case listener <- message:
+		default:
+			// a slow listener misses messages rather than blocking the logger
+		}
+	}
+}
+
+// recent gets the stored messages oldest first
+func (t *tail) recent() []Message {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.recentLocked()
+}
+
+func (t *tail) recentLocked() []Message {
+	if !t.full {
+		return append([]Message(nil), t.messages[:t.next]...)
+	}
+
+	result := make([]Message, 0, len(t.messages))
+	result = append(result, t.messages[t.next:]...)
+	return append(result, t.messages[:t.next]...)
+}
+
+// listen gets the stored messages and a channel of the new messages until the returned function is called
+func (t *tail) listen() ([]Message, chan Message, func()) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	listener := make(chan Message, len(t.messages))
+	t.listeners[listener] = struct{}{}
+
+	return t.recentLocked(), listener, func() {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		delete(t.listeners, listener)
+	}
+}
+
+// tails are the tails registered on a logger
+type tails struct {
+	mutex sync.RWMutex
+	items []*tail
+}
+
+func (t *tails) register(size int) *tail {
+	result := newTail(size)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.items = append(t.items, result)
+	return result
+}
+
+func (t *tails) add(message Message) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	for _, item := range t.items {
+		item.add(message)
+	}
+}
+
+// tailSource is implemented by loggers that can feed a TailHandler
+type tailSource interface {
+	tail(size int) *tail
+}
+
+func (l logger) tail(size int) *tail {
+	return l.tails.register(size)
+}
+
+func (t teeLog) tail(size int) *tail {
+	for _, l := range t.logs {
+		if source, ok := unwrap(l).(tailSource); ok {
+			return source.tail(size)
+		}
+	}
+	return nil
+}
+
+// TailHandler serves the last n messages logged by l as a JSON array, intended for local debugging.
+// The level query parameter only returns messages at or above that level e.g. ?level=Warning.
+// Requests that accept text/event-stream or set follow=true get the messages as server-sent events
+// and then keep receiving new messages as they are logged.
+func TailHandler(l ILog, n int) http.Handler {
+	source, ok := unwrap(l).(tailSource)
+	if !ok {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "logger does not support tailing", http.StatusNotImplemented)
+		})
+	}
+
+	t := source.tail(n)
+	if t == nil {
+		return http.NotFoundHandler()
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// every level is included unless a level is asked for, including trace and custom levels
+		minSeverity := math.MinInt32
+		if levelText := r.URL.Query().Get("level"); levelText != "" {
+			minSeverity = GetLogLevel(levelText).Severity
+		}
+
+		if strings.Contains(r.Header.Get("Accept"), "text/event-stream") || r.URL.Query().Get("follow") == "true" {
+			streamTail(w, r, t, minSeverity)
+			return
+		}
+
+		result := make([]Message, 0)
+		for _, message := range t.recent() {
+			if message.Level.Severity >= minSeverity {
+				result = append(result, message)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result)
+	})
+}
+
+func streamTail(w http.ResponseWriter, r *http.Request, t *tail, minSeverity int) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	recent, listener, stop := t.listen()
+	defer stop()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	write := func(message Message) bool {
+		if message.Level.Severity < minSeverity {
+			return true
+		}
+
+		data, err := json.Marshal(message)
+		if err != nil {
+			return true
+		}
+
+		if _, err = fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+			return false
+		}
+
+		flusher.Flush()
+		return true
+	}
+
+	for _, message := range recent {
+		if !write(message) {
+			return
+		}
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case message := <-listener:
+			if !write(message) {
+				return
+			}
+		}
+	}
+}
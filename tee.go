@@ -0,0 +1,326 @@
+package log
+
+import (
+	"context"
+	"io"
+	"log"
+	"time"
+)
+
+type teeLog struct {
+	logs []ILog
+}
+
+// Tee creates a logger that forwards every call to each of the given loggers in order,
+// e.g. to keep writing to a legacy logger while migrating to this one
+func Tee(logs ...ILog) ILog {
+	return teeLog{logs: logs}
+}
+
+// Warnf Print a formatted warning level message
+func (t teeLog) Warnf(format string, v ...interface{}) {
+	for _, l := range t.logs {
+		l.Warnf(format, v...)
+	}
+}
+
+// Warn Print a warning message
+func (t teeLog) Warn(v ...interface{}) {
+	for _, l := range t.logs {
+		l.Warn(v...)
+	}
+}
+
+// Error Print a error level message
+func (t teeLog) Error(err error, v ...interface{}) {
+	for _, l := range t.logs {
+		l.Error(err, v...)
+	}
+}
+
+// Errorf Print a formatted error level message
+func (t teeLog) Errorf(err error, format string, v ...interface{}) {
+	for _, l := range t.logs {
+		l.Errorf(err, format, v...)
+	}
+}
+
+// recoverFatal runs a loggers fatal call without letting its panic stop the other loggers
+func recoverFatal(fatal func()) {
+	defer func() {
+		_ = recover()
+	}()
+	fatal()
+}
+
+// Fatal print fatal level message to every logger then panics
+func (t teeLog) Fatal(err error, v ...interface{}) {
+	for _, l := range t.logs {
+		l := l
+		recoverFatal(func() { l.Fatal(err, v...) })
+	}
+	log.Panic(v...)
+}
+
+// Fatalf print formatted fatal level message to every logger then panics
+func (t teeLog) Fatalf(err error, format string, v ...interface{}) {
+	for _, l := range t.logs {
+		l := l
+		recoverFatal(func() { l.Fatalf(err, format, v...) })
+	}
+	log.Panicf(format, v...)
+}
+
+// Debug print debug level message
+func (t teeLog) Debug(v ...interface{}) {
+	for _, l := range t.logs {
+		l.Debug(v...)
+	}
+}
+
+// Debugf print formatted debug level  message
+func (t teeLog) Debugf(format string, v ...interface{}) {
+	for _, l := range t.logs {
+		l.Debugf(format, v...)
+	}
+}
+
+// Print print info level message
+func (t teeLog) Print(v ...interface{}) {
+	for _, l := range t.logs {
+		l.Print(v...)
+	}
+}
+
+// Printf print info level message
+func (t teeLog) Printf(format string, v ...interface{}) {
+	for _, l := range t.logs {
+		l.Printf(format, v...)
+	}
+}
+
+// GetWriter gets a writer that writes to each of the loggers
+func (t teeLog) GetWriter(level Level) io.Writer {
+	writers := make([]io.Writer, 0, len(t.logs))
+	for _, l := range t.logs {
+		writers = append(writers, l.GetWriter(level))
+	}
+	return io.MultiWriter(writers...)
+}
+
+// logBatch logs the messages to each of the loggers
+func (t teeLog) logBatch(messages []Message) {
+	for _, l := range t.logs {
+		LogBatch(l, messages)
+	}
+}
+
+// Close closes each of the loggers returning the first error
+func (t teeLog) Close() error {
+	var result error
+	for _, l := range t.logs {
+		if err := l.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// query logs the query to each of the loggers
+func (t teeLog) query(sql string, args []interface{}, dur time.Duration, err error, rows []int64) {
+	for _, l := range t.logs {
+		Query(l, sql, args, dur, err, rows...)
+	}
+}
+
+// hexDump logs the data to each of the loggers
+func (t teeLog) hexDump(level Level, label string, data []byte) {
+	for _, l := range t.logs {
+		HexDump(l, level, label, data)
+	}
+}
+
+// Pause pauses publishing on each of the loggers
+func (t teeLog) Pause() {
+	for _, l := range t.logs {
+		l.Pause()
+	}
+}
+
+// Resume resumes publishing on each of the loggers
+func (t teeLog) Resume() {
+	for _, l := range t.logs {
+		l.Resume()
+	}
+}
+
+// infoObj logs the object to each of the loggers
+func (t teeLog) infoObj(summary string, obj interface{}) {
+	for _, l := range t.logs {
+		InfoObj(l, summary, obj)
+	}
+}
+
+// beginRequest begins a request on each of the loggers
+func (t teeLog) beginRequest() (ILog, func(emit bool)) {
+	logs := make([]ILog, len(t.logs))
+	ends := make([]func(emit bool), len(t.logs))
+	for i, l := range t.logs {
+		logs[i], ends[i] = BeginRequest(l)
+	}
+
+	return teeLog{logs: logs}, func(emit bool) {
+		for _, end := range ends {
+			end(emit)
+		}
+	}
+}
+
+// WithField adds the field to each of the loggers
+func (t teeLog) WithField(key string, value interface{}) ILog {
+	logs := make([]ILog, len(t.logs))
+	for i, l := range t.logs {
+		logs[i] = l.WithField(key, value)
+	}
+	return teeLog{logs: logs}
+}
+
+// With adds the typed fields to each of the loggers
+func (t teeLog) With(fields ...Field) ILog {
+	logs := make([]ILog, len(t.logs))
+	for i, l := range t.logs {
+		logs[i] = l.With(fields...)
+	}
+	return teeLog{logs: logs}
+}
+
+// WithFields adds the fields to each of the loggers
+func (t teeLog) WithFields(fields map[string]interface{}) ILog {
+	logs := make([]ILog, len(t.logs))
+	for i, l := range t.logs {
+		logs[i] = l.WithFields(fields)
+	}
+	return teeLog{logs: logs}
+}
+
+// Flush flushes each of the loggers
+func (t teeLog) Flush() {
+	for _, l := range t.logs {
+		l.Flush()
+	}
+}
+
+// WithContext adds the context values to each of the loggers
+func (t teeLog) WithContext(ctx context.Context) ILog {
+	logs := make([]ILog, len(t.logs))
+	for i, l := range t.logs {
+		logs[i] = l.WithContext(ctx)
+	}
+	return teeLog{logs: logs}
+}
+
+// SetLevel sets the level of each of the loggers
+func (t teeLog) SetLevel(level Level) {
+	for _, l := range t.logs {
+		l.SetLevel(level)
+	}
+}
+
+// Named names each of the loggers
+func (t teeLog) Named(name string) ILog {
+	logs := make([]ILog, len(t.logs))
+	for i, l := range t.logs {
+		logs[i] = l.Named(name)
+	}
+	return teeLog{logs: logs}
+}
+
+func (t teeLog) logFatal(text string, fields map[string]interface{}) {
+	for _, l := range t.logs {
+		fatal(l, text, fields)
+	}
+}
+
+// Child creates a child of each of the loggers
+func (t teeLog) Child(name string, fields ...Field) ILog {
+	logs := make([]ILog, len(t.logs))
+	for i, l := range t.logs {
+		logs[i] = l.Child(name, fields...)
+	}
+	return teeLog{logs: logs}
+}
+
+// enabled is true if any of the loggers would log the level
+func (t teeLog) enabled(level Level) bool {
+	for _, l := range t.logs {
+		if enabled(l, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// clock gets the clock of the first logger that has one
+func (t teeLog) clock() Clock {
+	for _, l := range t.logs {
+		if c, ok := unwrap(l).(interface{ clock() Clock }); ok {
+			return c.clock()
+		}
+	}
+	return realClock{}
+}
+
+// addHook adds the hook to each of the loggers
+func (t teeLog) addHook(hook Hook) {
+	for _, l := range t.logs {
+		AddHook(l, hook)
+	}
+}
+
+// getStats adds up the counts of each of the loggers
+func (t teeLog) getStats() Stats {
+	var result Stats
+	for _, l := range t.logs {
+		result.add(GetStats(l))
+	}
+	return result
+}
+
+// Trace print trace level message
+func (t teeLog) Trace(v ...interface{}) {
+	for _, l := range t.logs {
+		l.Trace(v...)
+	}
+}
+
+// Tracef print formatted trace level message
+func (t teeLog) Tracef(format string, v ...interface{}) {
+	for _, l := range t.logs {
+		l.Tracef(format, v...)
+	}
+}
+
+// Log print a message at the level
+func (t teeLog) Log(level Level, v ...interface{}) {
+	for _, l := range t.logs {
+		l.Log(level, v...)
+	}
+}
+
+// Logf print a formatted message at the level
+func (t teeLog) Logf(level Level, format string, v ...interface{}) {
+	for _, l := range t.logs {
+		l.Logf(level, format, v...)
+	}
+}
+
+// Shutdown shuts down each of the loggers returning the first error
+func (t teeLog) Shutdown(ctx context.Context) error {
+	var result error
+	for _, l := range t.logs {
+		if err := l.Shutdown(ctx); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package log
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHelpersUseWrappedLoggerSettings(t *testing.T) {
+	tests := []struct {
+		name string
+		wrap func(m *MemoryLogger) ILog
+	}{
+		{name: "memory", wrap: func(m *MemoryLogger) ILog { return m }},
+		{name: "tee", wrap: func(m *MemoryLogger) ILog { return Tee(m, NewNopLogger()) }},
+		{name: "nested tee", wrap: func(m *MemoryLogger) ILog { return Tee(Tee(m)) }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewMemoryLogger(Settings{MinLogLevel: DEBUG, SlowQueryThreshold: time.Millisecond, LogQueryArgs: true})
+			Query(test.wrap(m), "select ?", []interface{}{1}, 2*time.Millisecond, nil)
+
+			messages := m.Messages()
+			if len(messages) != 1 {
+				t.Fatalf("logged %d messages, expected 1", len(messages))
+			}
+			if messages[0].Level != WARNING {
+				t.Errorf("slow query logged at %s, expected %s", messages[0].Level.Text, WARNING.Text)
+			}
+			if _, ok := messages[0].Fields[QueryArgsField]; !ok {
+				t.Error("query arguments were not logged")
+			}
+		})
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      ILog
+		level    Level
+		expected bool
+	}{
+		{name: "below level", log: NewMemoryLogger(Settings{MinLogLevel: WARNING}), level: INFO, expected: false},
+		{name: "at level", log: NewMemoryLogger(Settings{MinLogLevel: WARNING}), level: WARNING, expected: true},
+		{name: "tee any enabled", log: Tee(NewMemoryLogger(Settings{MinLogLevel: ERROR}), NewMemoryLogger(Settings{MinLogLevel: DEBUG})), level: INFO, expected: true},
+		{name: "tee none enabled", log: Tee(NewMemoryLogger(Settings{MinLogLevel: ERROR}), NewMemoryLogger(Settings{MinLogLevel: WARNING})), level: INFO, expected: false},
+		{name: "nop", log: NewNopLogger(), level: FATAL, expected: false},
+		{name: "other package", log: struct{ ILog }{NewNopLogger()}, level: TRACE, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := enabled(test.log, test.level); result != test.expected {
+				t.Errorf("enabled is %t, expected %t", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestTailHandlerMemoryLogger(t *testing.T) {
+	m := NewMemoryLogger(Settings{MinLogLevel: INFO})
+	handler := TailHandler(m, 10)
+	m.Print("tailed")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status is %d, expected %d", recorder.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,46 @@
+package log
+
+import "time"
+
+// TimestampPolicy is what happens to messages with a time outside of Settings.TimestampWindow
+type TimestampPolicy string
+
+const (
+	// ClampTimestamps moves the time of the message to the closest edge of the window, it is the default
+	ClampTimestamps TimestampPolicy = "clamp"
+	// RejectTimestamps drops the message
+	RejectTimestamps TimestampPolicy = "reject"
+)
+
+// dropTimestamp is the drop reason for messages rejected because of their time
+const dropTimestamp = "timestamp"
+
+// checkTimestamp makes sure a message time given by the caller is within Settings.TimestampWindow of now,
+// false is returned if the message should be dropped
+func (l logger) checkTimestamp(message *Message, now int64) bool {
+	window := int64(l.settings.TimestampWindow / time.Millisecond)
+	if window <= 0 {
+		return true
+	}
+
+	earliest, latest := now-window, now+window
+	if message.Time >= earliest && message.Time <= latest {
+		return true
+	}
+
+	if l.settings.TimestampPolicy == RejectTimestamps {
+		l.internal().Printf("Dropping log with time %s outside of %s of now: %s", time.Unix(0, message.Time*int64(time.Millisecond)).UTC(), l.settings.TimestampWindow, message.Text)
+		l.dropped(dropTimestamp)
+		return false
+	}
+
+	original := message.Time
+	if message.Time < earliest {
+		message.Time = earliest
+	} else {
+		message.Time = latest
+	}
+
+	l.internal().Printf("Clamped log time %s to within %s of now: %s", time.Unix(0, original*int64(time.Millisecond)).UTC(), l.settings.TimestampWindow, message.Text)
+	return true
+}
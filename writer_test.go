@@ -0,0 +1,45 @@
+package log
+
+import "testing"
+
+func TestWriterLogsEachLine(t *testing.T) {
+	l := NewMemoryLogger(Settings{MinLogLevel: INFO})
+	w := l.GetWriter(WARNING)
+
+	input := []byte("first\r\nsecond\n\nthird\n")
+	n, err := w.Write(input)
+	if err != nil || n != len(input) {
+		t.Fatalf("Write returned %d, %v expected %d, nil", n, err, len(input))
+	}
+
+	messages := l.Messages()
+	expected := []string{"first", "second", "third"}
+	if len(messages) != len(expected) {
+		t.Fatalf("logged %d messages, expected %d", len(messages), len(expected))
+	}
+
+	for i, message := range messages {
+		if message.Text != expected[i] {
+			t.Errorf("message %d is %q, expected %q", i, message.Text, expected[i])
+		}
+		if message.Level != WARNING {
+			t.Errorf("message %d is at %s, expected %s", i, message.Level.Text, WARNING.Text)
+		}
+	}
+}
+
+func TestWriterEmptyWrite(t *testing.T) {
+	l := NewMemoryLogger(Settings{MinLogLevel: INFO})
+	w := l.GetWriter(INFO)
+
+	for _, input := range [][]byte{nil, {}, []byte("\n\n")} {
+		n, err := w.Write(input)
+		if err != nil || n != len(input) {
+			t.Errorf("Write(%q) returned %d, %v expected %d, nil", input, n, err, len(input))
+		}
+	}
+
+	if messages := l.Messages(); len(messages) != 0 {
+		t.Errorf("logged %d messages for empty writes", len(messages))
+	}
+}